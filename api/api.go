@@ -0,0 +1,260 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/definitions"
+	"github.com/nihei9/felipe/query"
+)
+
+// Source returns the components the API answers about. It is called on every request, so it can
+// return the latest components of reloaded definitions.
+type Source func() (*component.Components, error)
+
+// Handler serves components in JSON with the following endpoints.
+//
+//	GET /components
+//	GET /components/{id}
+//	GET /components/{id}/dependencies?depth=
+//	GET /query?filter=&complement=
+//	GET /paths?from=&to=&limit=
+//
+// Components are written in the same structure as components definitions. Hidden components are
+// not exposed. IDs in paths can be escaped (e.g. `%2F` for `/`).
+type Handler struct {
+	Source Source
+
+	// Prefix is removed from paths of requests when the handler is mounted under a path (e.g. `/api`).
+	Prefix string
+}
+
+func New(source Source) *Handler {
+	return &Handler{
+		Source: source,
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type pathsResponse struct {
+	Paths [][]component.ComponentID `json:"paths"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method `%s` is not allowed", r.Method))
+		return
+	}
+
+	segments, err := splitPath(strings.TrimPrefix(r.URL.EscapedPath(), h.Prefix))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cs, err := h.Source()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	switch {
+	case len(segments) == 1 && segments[0] == "components":
+		writeComponents(w, cs, cs)
+	case len(segments) == 2 && segments[0] == "components":
+		h.component(w, cs, segments[1])
+	case len(segments) == 3 && segments[0] == "components" && segments[2] == "dependencies":
+		h.dependencies(w, r, cs, segments[1])
+	case len(segments) == 1 && segments[0] == "query":
+		result, err := Query(cs, r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeComponents(w, result, cs)
+	case len(segments) == 1 && segments[0] == "paths":
+		h.paths(w, r, cs)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("`%s` is not found", r.URL.Path))
+	}
+}
+
+func (h *Handler) component(w http.ResponseWriter, cs *component.Components, id string) {
+	c, ok := lookup(cs, id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("the component `%s` is undefined", id))
+		return
+	}
+
+	result := component.NewComponents()
+	result.Add(c)
+	writeJSON(w, http.StatusOK, definitions.MakeComponentsDefinition(visible(result, cs)).Components[0])
+}
+
+func (h *Handler) dependencies(w http.ResponseWriter, r *http.Request, cs *component.Components, id string) {
+	c, ok := lookup(cs, id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("the component `%s` is undefined", id))
+		return
+	}
+
+	depth := 1
+	if d := r.URL.Query().Get("depth"); d != "" {
+		var err error
+		depth, err = strconv.Atoi(d)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("`depth` must be an integer; got: %v", d))
+			return
+		}
+	}
+
+	start := component.NewComponents()
+	start.Add(c)
+	result, err := query.DependenciesComplementer{
+		AllComponents: cs,
+		Depth:         depth,
+	}.Complement(start)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	deps := component.NewComponents()
+	for _, depID := range result.GetIDs() {
+		if depID == c.ID {
+			continue
+		}
+		d, _ := result.Get(depID)
+		deps.Add(d)
+	}
+	writeComponents(w, deps, cs)
+}
+
+func (h *Handler) paths(w http.ResponseWriter, r *http.Request, cs *component.Components) {
+	params := r.URL.Query()
+	from, to := params.Get("from"), params.Get("to")
+	if from == "" || to == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("`from` and `to` must be specified"))
+		return
+	}
+	limit := query.DefaultPathLimit
+	if l := params.Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("`limit` must be an integer; got: %v", l))
+			return
+		}
+	}
+
+	// Paths are found among visible components so that they do not pass through hidden ones.
+	writeJSON(w, http.StatusOK, &pathsResponse{
+		Paths: query.FindPaths(visible(cs, cs), component.ComponentID(from), component.ComponentID(to), limit),
+	})
+}
+
+// Query runs a query specified by `filter` and `complement` parameters. Their syntax is the same as
+// the flags of the query command.
+func Query(cs *component.Components, params url.Values) (*component.Components, error) {
+	var filter query.Filter = query.AllPassFilter{}
+	if f := params.Get("filter"); f != "" {
+		var err error
+		filter, err = query.ParseFilter(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var complementer query.Complementer = query.DependenciesComplementer{
+		AllComponents: cs,
+		Depth:         -1,
+	}
+	if c := params.Get("complement"); c != "" {
+		var err error
+		complementer, err = query.ParseComplementation(c, cs, query.Traversal{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return query.Query{
+		Components:   cs,
+		Filter:       filter,
+		Complementer: complementer,
+	}.Do()
+}
+
+// lookup returns a component that the API exposes. Hidden components such as templates are
+// treated as undefined.
+func lookup(cs *component.Components, id string) (*component.Component, bool) {
+	c, ok := cs.Get(component.ComponentID(id))
+	if !ok || c.IsHidden() {
+		return nil, false
+	}
+
+	return c, true
+}
+
+// splitPath splits an escaped path into unescaped segments so that IDs can contain `/`.
+func splitPath(path string) ([]string, error) {
+	segments := []string{}
+	for _, s := range strings.Split(strings.Trim(path, "/"), "/") {
+		if s == "" {
+			continue
+		}
+		seg, err := url.PathUnescape(s)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+
+	return segments, nil
+}
+
+// writeComponents writes components except hidden ones. all is the set of components that the
+// dependencies of cs refer to.
+func writeComponents(w http.ResponseWriter, cs *component.Components, all *component.Components) {
+	writeJSON(w, http.StatusOK, definitions.MakeComponentsDefinition(visible(cs, all)))
+}
+
+// visible returns components in cs except hidden ones. Dependencies on components hidden in all are
+// removed as well, so that responses never refer to hidden components.
+func visible(cs *component.Components, all *component.Components) *component.Components {
+	result := component.NewComponents()
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		if c.IsHidden() {
+			continue
+		}
+		c = c.Clone()
+		for depID := range c.Dependencies {
+			if d, ok := all.Get(depID); ok && d.IsHidden() {
+				delete(c.Dependencies, depID)
+			}
+		}
+		result.Add(c)
+	}
+
+	return result
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &errorResponse{
+		Error: err.Error(),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,277 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/definitions"
+)
+
+func TestHandler(t *testing.T) {
+	// a -> b -> c -> d, a -> team/e, a -> proxy -> d
+	// proxy is hidden.
+	cs := component.NewComponents()
+	a := component.NewComponent(component.NilComponentID, "a")
+	a.AddLabel("role", "entry")
+	a.DependOn("b", &component.Relation{Description: "calls"})
+	a.DependOn("team/e", &component.Relation{})
+	a.DependOn("proxy", &component.Relation{})
+	cs.Add(a)
+	b := component.NewComponent(component.NilComponentID, "b")
+	b.DependOn("c", &component.Relation{})
+	cs.Add(b)
+	c := component.NewComponent(component.NilComponentID, "c")
+	c.DependOn("d", &component.Relation{})
+	cs.Add(c)
+	cs.Add(component.NewComponent(component.NilComponentID, "d"))
+	cs.Add(component.NewComponent(component.NilComponentID, "team/e"))
+	proxy := component.NewComponent(component.NilComponentID, "proxy")
+	proxy.AddLabel("role", "entry")
+	proxy.DependOn("d", &component.Relation{})
+	proxy.Hide()
+	cs.Add(proxy)
+
+	ts := httptest.NewServer(New(func() (*component.Components, error) {
+		return cs, nil
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		caption string
+		path    string
+		status  int
+		ids     []string
+	}{
+		{
+			caption: "all components",
+			path:    "/components",
+			status:  http.StatusOK,
+			ids:     []string{"a", "b", "c", "d", "team/e"},
+		},
+		{
+			caption: "direct dependencies",
+			path:    "/components/a/dependencies",
+			status:  http.StatusOK,
+			ids:     []string{"b", "team/e"},
+		},
+		{
+			caption: "dependencies within a depth",
+			path:    "/components/a/dependencies?depth=2",
+			status:  http.StatusOK,
+			ids:     []string{"b", "c", "d", "team/e"},
+		},
+		{
+			caption: "all dependencies",
+			path:    "/components/b/dependencies?depth=-1",
+			status:  http.StatusOK,
+			ids:     []string{"c", "d"},
+		},
+		{
+			caption: "dependencies of an escaped ID",
+			path:    "/components/team%2Fe/dependencies",
+			status:  http.StatusOK,
+			ids:     []string{},
+		},
+		{
+			caption: "malformed depth",
+			path:    "/components/a/dependencies?depth=x",
+			status:  http.StatusBadRequest,
+		},
+		{
+			caption: "dependencies of an undefined component",
+			path:    "/components/x/dependencies",
+			status:  http.StatusNotFound,
+		},
+		{
+			caption: "dependencies of a hidden component",
+			path:    "/components/proxy/dependencies",
+			status:  http.StatusNotFound,
+		},
+		{
+			caption: "query",
+			path:    "/query?filter=role%3Dentry&complement=dep%3D1",
+			status:  http.StatusOK,
+			ids:     []string{"a", "b", "team/e"},
+		},
+		{
+			caption: "query without parameters",
+			path:    "/query",
+			status:  http.StatusOK,
+			ids:     []string{"a", "b", "c", "d", "team/e"},
+		},
+		{
+			caption: "malformed query",
+			path:    "/query?complement=foo",
+			status:  http.StatusBadRequest,
+		},
+		{
+			caption: "unknown path",
+			path:    "/foo",
+			status:  http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			res, err := http.Get(ts.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			if res.StatusCode != tt.status {
+				t.Fatalf("unexpected status; want: %v, got: %v", tt.status, res.StatusCode)
+			}
+			if tt.status != http.StatusOK {
+				e := &errorResponse{}
+				err := json.NewDecoder(res.Body).Decode(e)
+				if err != nil || e.Error == "" {
+					t.Errorf("an error message must be returned; got: %+v, %v", e, err)
+				}
+				return
+			}
+
+			def := &definitions.ComponentsDefinition{}
+			err = json.NewDecoder(res.Body).Decode(def)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := []string{}
+			for _, c := range def.Components {
+				ids = append(ids, c.ID)
+			}
+			sort.Strings(ids)
+			if !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("unexpected components; want: %v, got: %v", tt.ids, ids)
+			}
+		})
+	}
+}
+
+func TestHandlerComponent(t *testing.T) {
+	// a -> b, a -> team/e, a -> proxy (hidden)
+	cs := component.NewComponents()
+	a := component.NewComponent(component.NilComponentID, "a")
+	a.AddLabel("role", "entry")
+	a.DependOn("b", &component.Relation{Description: "calls"})
+	a.DependOn("team/e", &component.Relation{})
+	a.DependOn("proxy", &component.Relation{})
+	cs.Add(a)
+	proxy := component.NewComponent(component.NilComponentID, "proxy")
+	proxy.Hide()
+	cs.Add(proxy)
+	h := &Handler{
+		Source: func() (*component.Components, error) {
+			return cs, nil
+		},
+		Prefix: "/api",
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/components/a", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status; got: %v", rec.Code)
+	}
+	c := &definitions.Component{}
+	err := json.NewDecoder(rec.Body).Decode(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &definitions.Component{
+		ID:     "a",
+		Labels: map[string]string{"role": "entry"},
+		Dependencies: []*definitions.DependentComponent{
+			{ID: "b", Relation: "calls"},
+			{ID: "team/e"},
+		},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("unexpected component; want: %+v, got: %+v", want, c)
+	}
+
+	for _, id := range []string{"x", "proxy"} {
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/components/"+id, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("unexpected status of `%s`; got: %v", id, rec.Code)
+		}
+	}
+}
+
+func TestHandlerPaths(t *testing.T) {
+	// a -> b -> c -> d, a -> proxy (hidden) -> d
+	cs := component.NewComponents()
+	for _, e := range []struct {
+		id     component.ComponentID
+		deps   []component.ComponentID
+		hidden bool
+	}{
+		{id: "a", deps: []component.ComponentID{"b", "proxy"}},
+		{id: "b", deps: []component.ComponentID{"c"}},
+		{id: "c", deps: []component.ComponentID{"d"}},
+		{id: "d"},
+		{id: "proxy", deps: []component.ComponentID{"d"}, hidden: true},
+	} {
+		c := component.NewComponent(component.NilComponentID, e.id)
+		for _, d := range e.deps {
+			c.DependOn(d, &component.Relation{})
+		}
+		if e.hidden {
+			c.Hide()
+		}
+		cs.Add(c)
+	}
+	h := New(func() (*component.Components, error) {
+		return cs, nil
+	})
+
+	tests := []struct {
+		caption string
+		path    string
+		paths   [][]component.ComponentID
+	}{
+		{
+			caption: "paths do not pass through hidden components",
+			path:    "/paths?from=a&to=d",
+			paths:   [][]component.ComponentID{{"a", "b", "c", "d"}},
+		},
+		{
+			caption: "a hidden component is not found",
+			path:    "/paths?from=a&to=proxy",
+			paths:   [][]component.ComponentID{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("unexpected status; got: %v", rec.Code)
+			}
+			res := &pathsResponse{}
+			err := json.NewDecoder(rec.Body).Decode(res)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(res.Paths, tt.paths) {
+				t.Errorf("unexpected paths; want: %v, got: %v", tt.paths, res.Paths)
+			}
+		})
+	}
+}
+
+func TestHandlerSourceError(t *testing.T) {
+	h := New(func() (*component.Components, error) {
+		return nil, errors.New("invalid definitions")
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/components", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("unexpected status; got: %v", rec.Code)
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/nihei9/felipe/api"
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/loader"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagAddr     string
+	flagOverlays []string
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api <dir>",
+		Short: "api serves a JSON API to query components.",
+		Long:  "api serves a JSON API to query components defined in the directory.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  run,
+	}
+	cmd.Flags().StringVarP(&flagAddr, "addr", "a", "localhost:8080", "address to listen on")
+	cmd.Flags().StringSliceVar(&flagOverlays, "overlay", []string{}, "overlays applied to the definitions; a name refers to <dir>/overlays/<name>.yaml")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	cs, err := loader.LoadDir(args[0], flagOverlays...)
+	if err != nil {
+		return err
+	}
+
+	h := api.New(func() (*component.Components, error) {
+		return cs, nil
+	})
+
+	fmt.Fprintf(os.Stderr, "serving on http://%s\n", flagAddr)
+	return http.ListenAndServe(flagAddr, h)
+}
@@ -0,0 +1,145 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nihei9/felipe/cmd/felipe/outfile"
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/definitions"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/loader"
+	"github.com/nihei9/felipe/render"
+	"github.com/spf13/cobra"
+)
+
+const defaultFormat = "svg"
+
+var (
+	flagViewsFile string
+	flagOutDir    string
+	flagOverlays  []string
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build <dir>",
+		Short: "build generates all views defined in a views definition.",
+		Long:  "build generates all views defined in a views definition from components defined in the directory and writes them into the output directory.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  run,
+	}
+	cmd.Flags().StringVarP(&flagViewsFile, "views", "v", "", "file path that defines views")
+	cmd.Flags().StringVarP(&flagOutDir, "out_dir", "o", "", "directory to write the views to")
+	cmd.Flags().StringSliceVar(&flagOverlays, "overlay", []string{}, "overlays applied to the definitions; a name refers to <dir>/overlays/<name>.yaml")
+	cmd.MarkFlagRequired("views")
+	cmd.MarkFlagRequired("out_dir")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	def, err := readViewsDefinition(flagViewsFile)
+	if err != nil {
+		return fmt.Errorf("%s: %v", flagViewsFile, err)
+	}
+
+	// Views and faces definitions can be kept in the directory of components.
+	excludes := []string{flagViewsFile}
+	for _, v := range def.Views {
+		if v.Format == "" {
+			v.Format = defaultFormat
+		}
+		_, err := render.Lookup(v.Format)
+		if err != nil {
+			return fmt.Errorf("view `%s`: %v", v.Name, err)
+		}
+		if v.Faces != "" {
+			v.Faces = resolvePath(flagViewsFile, v.Faces)
+			excludes = append(excludes, v.Faces)
+		}
+	}
+
+	defFiles, err := loader.DefinitionFiles(args[0], excludes...)
+	if err != nil {
+		return err
+	}
+	overlayFiles := []string{}
+	for _, name := range flagOverlays {
+		overlayFiles = append(overlayFiles, loader.OverlayFile(args[0], name))
+	}
+	cs, err := loader.LoadFilesWithOverlays(defFiles, overlayFiles)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(flagOutDir, 0755)
+	if err != nil {
+		return err
+	}
+	for _, v := range def.Views {
+		path, err := buildView(cs, v)
+		if err != nil {
+			return fmt.Errorf("view `%s`: %v", v.Name, err)
+		}
+		fmt.Println(path)
+	}
+
+	return nil
+}
+
+func buildView(cs *component.Components, v *definitions.View) (string, error) {
+	group, err := definitions.MakeQuerySpec(v).Group(cs)
+	if err != nil {
+		return "", err
+	}
+
+	fs := []*face.Face{}
+	graph := &face.Graph{}
+	if v.Faces != "" {
+		faces, err := loader.LoadFaces(v.Faces)
+		if err != nil {
+			return "", err
+		}
+
+		fs = faces.Faces
+		graph = faces.Graph
+	}
+	graph.GroupBy = v.GroupBy
+
+	r, err := render.Lookup(v.Format)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(flagOutDir, v.Name+"."+v.Format)
+	err = outfile.Write(path, func(w io.Writer) error {
+		return r.Render(w, group, cs, fs, graph)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// resolvePath resolves a path written in a definition relative to the definition file.
+func resolvePath(defFile string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(defFile), path)
+}
+
+func readViewsDefinition(filePath string) (*definitions.ViewsDefinition, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return definitions.ReadViewsDefinition(f)
+}
@@ -4,236 +4,104 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strings"
 
-	"github.com/awalterschulze/gographviz"
-	"github.com/nihei9/felipe/component"
-	"github.com/nihei9/felipe/definitions"
+	"github.com/nihei9/felipe/cmd/felipe/outfile"
+	"github.com/nihei9/felipe/cmd/felipe/queryflag"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/loader"
 	"github.com/nihei9/felipe/query"
+	"github.com/nihei9/felipe/render"
+	"github.com/nihei9/felipe/watch"
 	"github.com/spf13/cobra"
 )
 
-type Face struct {
-	Filter     query.Filter
-	Attributes map[string]string
-}
-
 var (
 	flagSrcFile  string
 	flagFaceFile string
+	flagSpec     query.Spec
+	flagOverlays []string
+	flagOutFile  string
+	flagWatch    bool
 )
 
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "dot",
+		Use:   "dot [dir]",
 		Short: "dot generate .dot files.",
-		Long:  "dot generate .dot files.",
+		Long:  "dot generate .dot files from components defined in the directory, the source file or stdin. Query flags select the components to draw.",
+		Args:  cobra.MaximumNArgs(1),
 		RunE:  run,
 	}
 	cmd.Flags().StringVarP(&flagSrcFile, "src_file", "s", "", "file path that defines components (default: stdin)")
 	cmd.Flags().StringVarP(&flagFaceFile, "face", "f", "", "file path that defines faces for image generates from DOT")
+	queryflag.Add(cmd, &flagSpec, "", "c")
+	cmd.Flags().StringSliceVar(&flagOverlays, "overlay", []string{}, "overlays applied to the definitions; a name refers to <dir>/overlays/<name>.yaml")
+	cmd.Flags().StringVarP(&flagOutFile, "out_file", "o", "", "file path to write DOT to (default: stdout)")
+	cmd.Flags().BoolVarP(&flagWatch, "watch", "w", false, "rewrite the output every time the definition or faces files change")
 
 	return cmd
 }
 
 func run(cmd *cobra.Command, args []string) error {
-	def, err := readComponentsDefinition(flagSrcFile)
+	src, err := queryflag.Source(args, flagSrcFile)
 	if err != nil {
 		return err
 	}
 
-	cs := component.NewComponents()
-	for _, cDef := range def.Components {
-		c := definitions.MakeComponentEntity(cDef)
-		cs.Add(c)
+	generate := func() error {
+		return generateDOT(src)
 	}
-	err = cs.Complement()
-	if err != nil {
-		return err
+	if !flagWatch {
+		return generate()
 	}
 
-	fs := []*Face{}
-	if flagFaceFile != "" {
-		def, err := readFacesDefinition(flagFaceFile)
-		if err != nil {
-			return err
-		}
-
-		for _, fDef := range def.Faces {
-			f := &Face{
-				Filter: query.LabelsFilter{
-					Labels: fDef.Targets.MatchLabels,
-				},
-				Attributes: fDef.Attributes,
-			}
-			fs = append(fs, f)
-		}
+	if src == "" {
+		return fmt.Errorf("a directory or `--src_file` must be specified when `--watch` is specified")
 	}
-
-	err = writeDot(cs, cs, fs, os.Stdout)
-	if err != nil {
-		return err
+	paths := []string{src}
+	if flagFaceFile != "" {
+		paths = append(paths, flagFaceFile)
 	}
-
-	return nil
-}
-
-func readComponentsDefinition(filePath string) (*definitions.ComponentsDefinition, error) {
-	var r io.Reader
-	if filePath != "" {
-		f, err := os.Open(filePath)
-		if err != nil {
-			return nil, err
-		}
-		defer f.Close()
-		r = f
-	} else {
-		r = os.Stdin
+	for _, name := range flagOverlays {
+		paths = append(paths, loader.OverlayFile(src, name))
 	}
 
-	return definitions.ReadComponentsDefinition(r)
-}
-
-func readFacesDefinition(filePath string) (*definitions.FacesDefinition, error) {
-	f, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+	excludes := []string{}
+	if flagOutFile != "" {
+		excludes = append(excludes, flagOutFile)
 	}
-	defer f.Close()
 
-	return definitions.ReadFacesDefinition(f)
+	return watch.Rerun(watch.Poller{
+		Paths:    paths,
+		Excludes: excludes,
+	}, nil, generate, func(err error) {
+		fmt.Fprintln(os.Stderr, err)
+	})
 }
 
-func writeDot(group *component.Components, cs *component.Components, fs []*Face, w io.Writer) error {
-	dot, err := genDot(group, cs, fs)
+func generateDOT(src string) error {
+	cs, err := queryflag.LoadComponents(src, flagOverlays)
 	if err != nil {
 		return err
 	}
-
-	_, err = fmt.Fprint(w, dot)
+	group, err := flagSpec.Group(cs)
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
-
-func genDot(group *component.Components, cs *component.Components, fs []*Face) (string, error) {
-	ast, _ := gographviz.ParseString("digraph G {}")
-	g := gographviz.NewGraph()
-	err := gographviz.Analyse(ast, g)
-	if err != nil {
-		return "", err
-	}
-	g.AddAttr("G", "rankdir", "LR")
-	g.AddAttr("G", "fontsize", "11.0")
-
-	for _, id := range group.GetIDs() {
-		c, _ := group.Get(id)
-		nAttrs, err := genAttributes(c, fs)
-		if err != nil {
-			return "", err
-		}
-		nAttrs["penwidth"] = "0.75"
-		err = g.AddNode("G", fmt.Sprintf("\"%s\"", c.ID.String()), nAttrs)
-		if err != nil {
-			return "", err
-		}
-		for dcid, rel := range c.Dependencies {
-			d, ok := cs.Get(dcid)
-			if !ok {
-				continue
-			}
-			nAttrs, err := genAttributes(d, fs)
-			nAttrs["penwidth"] = "0.75"
-			if err != nil {
-				return "", err
-			}
-			err = g.AddNode("G", fmt.Sprintf("\"%s\"", d.ID.String()), nAttrs)
-			if err != nil {
-				return "", err
-			}
-
-			eAttrs := map[string]string{
-				"arrowsize": "0.75",
-				"penwidth":  "0.75",
-				"label":     fmt.Sprintf("\"%s\"", rel.Description),
-			}
-			err = g.AddEdge(fmt.Sprintf("\"%s\"", c.ID.String()), fmt.Sprintf("\"%s\"", d.ID.String()), true, eAttrs)
-			if err != nil {
-				return "", err
-			}
-		}
-	}
-
-	return g.String(), nil
-}
-
-func genAttributes(c *component.Component, fs []*Face) (map[string]string, error) {
-	attrs := map[string]string{}
-	for _, f := range fs {
-		pass, err := f.Filter.Pass(c)
+	fs := []*face.Face{}
+	var graph *face.Graph
+	if flagFaceFile != "" {
+		faces, err := loader.LoadFaces(flagFaceFile)
 		if err != nil {
-			return nil, err
-		}
-		if !pass {
-			continue
-		}
-		for k, v := range f.Attributes {
-			if k == "label" {
-				label, err := constructLabel(c, v)
-				if err != nil {
-					return nil, err
-				}
-				attrs[k] = label
-			} else {
-				attrs[k] = v
-			}
-		}
-	}
-
-	return attrs, nil
-}
-
-func constructLabel(c *component.Component, template string) (string, error) {
-	placeholders := []string{}
-	capture := false
-	var start int
-	var end int
-	for i, char := range template {
-		switch char {
-		case '{':
-			if capture {
-				return "", fmt.Errorf("an embeded label cannot be nested")
-			}
-			capture = true
-			start = i
-		case '}':
-			if !capture {
-				return "", fmt.Errorf("an embeded label is malformed")
-			}
-			capture = false
-			end = i
-
-			placeholder := template[start : end+1]
-			placeholders = append(placeholders, placeholder)
-		}
-	}
-
-	embeddedValues := []string{}
-	for _, p := range placeholders {
-		labelK := strings.TrimSpace(p[1 : len(p)-1])
-		labelV, ok := c.Labels[labelK]
-		if !ok {
-			return "", fmt.Errorf("ID cannot include the undefined label `%s`", labelK)
+			return err
 		}
-		embeddedValues = append(embeddedValues, labelV)
-	}
 
-	label := template
-	for i, p := range placeholders {
-		label = strings.Replace(label, p, embeddedValues[i], 1)
+		fs = faces.Faces
+		graph = faces.Graph
 	}
 
-	return label, nil
+	return outfile.Write(flagOutFile, func(w io.Writer) error {
+		return render.WriteDOT(w, group, cs, fs, graph)
+	})
 }
@@ -0,0 +1,112 @@
+package drift
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nihei9/felipe/definitions"
+	"github.com/nihei9/felipe/drift"
+	"github.com/nihei9/felipe/loader"
+	"github.com/spf13/cobra"
+)
+
+// ExitCodeDrift is the exit status when declared and actual components drift. Failures such as
+// malformed definitions exit with 1, so that CI can tell them apart.
+const ExitCodeDrift = 2
+
+type driftError struct{}
+
+func (e *driftError) Error() string {
+	return "declared and actual components drift"
+}
+
+func (e *driftError) ExitCode() int {
+	return ExitCodeDrift
+}
+
+var (
+	flagDeclaredDir string
+	flagActualDir   string
+	flagAliasesFile string
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Short: "drift reports differences between declared and actual components.",
+		Long:  "drift reports differences between declared and actual components. It exits with status 2 when they drift, and with status 1 when it fails to compare them.",
+		Args:  cobra.NoArgs,
+		RunE:  run,
+	}
+	cmd.Flags().StringVarP(&flagDeclaredDir, "declared", "d", "", "directory that contains declared component definitions")
+	cmd.Flags().StringVarP(&flagActualDir, "actual", "a", "", "directory that contains actual component definitions")
+	cmd.Flags().StringVarP(&flagAliasesFile, "aliases", "m", "", "file path that defines aliases of component IDs")
+	cmd.MarkFlagRequired("declared")
+	cmd.MarkFlagRequired("actual")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	// A mistyped directory must fail rather than be reported as drift of empty definitions.
+	for _, dir := range []string{flagDeclaredDir, flagActualDir} {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("`%s` is not a directory", dir)
+		}
+	}
+
+	declared, err := loader.LoadDir(flagDeclaredDir)
+	if err != nil {
+		return err
+	}
+	actual, err := loader.LoadDir(flagActualDir)
+	if err != nil {
+		return err
+	}
+
+	var aliases drift.Aliases
+	if flagAliasesFile != "" {
+		def, err := readAliasesDefinition(flagAliasesFile)
+		if err != nil {
+			return err
+		}
+		aliases = definitions.MakeAliases(def)
+	}
+
+	r := drift.Compare(declared, actual, aliases)
+	writeReport(r)
+	if r.HasDrift() {
+		return &driftError{}
+	}
+
+	return nil
+}
+
+func readAliasesDefinition(filePath string) (*definitions.AliasesDefinition, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return definitions.ReadAliasesDefinition(f)
+}
+
+func writeReport(r *drift.Report) {
+	for _, id := range r.MissingInActual {
+		fmt.Printf("missing in actual: %s\n", id)
+	}
+	for _, id := range r.MissingInDeclared {
+		fmt.Printf("missing in declared: %s\n", id)
+	}
+	for _, dep := range r.UndeclaredDependencies {
+		fmt.Printf("undeclared dependency: %s -> %s\n", dep.From, dep.To)
+	}
+	for _, dep := range r.UnusedDependencies {
+		fmt.Printf("unused dependency: %s -> %s\n", dep.From, dep.To)
+	}
+}
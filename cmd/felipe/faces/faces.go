@@ -0,0 +1,102 @@
+package faces
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/loader"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagSrcFile  string
+	flagFaceFile string
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "faces",
+		Short: "faces inspects faces definitions.",
+		Long:  "faces inspects faces definitions.",
+	}
+	cmd.AddCommand(newExplainCmd())
+
+	return cmd
+}
+
+func newExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain <component>",
+		Short: "explain shows which faces match a component and where each attribute comes from.",
+		Long:  "explain shows which faces match a component and where each attribute comes from.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runExplain,
+	}
+	cmd.Flags().StringVarP(&flagSrcFile, "src_file", "s", "", "file path that defines components (default: stdin)")
+	cmd.Flags().StringVarP(&flagFaceFile, "face", "f", "", "file path that defines faces")
+	cmd.MarkFlagRequired("face")
+
+	return cmd
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	cs, err := loadComponents(flagSrcFile)
+	if err != nil {
+		return err
+	}
+
+	c, ok := cs.Get(component.ComponentID(args[0]))
+	if !ok {
+		return fmt.Errorf("the component `%s` is undefined", args[0])
+	}
+
+	faces, err := loader.LoadFaces(flagFaceFile)
+	if err != nil {
+		return err
+	}
+
+	e, err := face.Engine{
+		Faces:      faces.Faces,
+		Components: cs,
+	}.Explain(c)
+	if err != nil {
+		return err
+	}
+
+	return writeExplanation(e)
+}
+
+func writeExplanation(e *face.Explanation) error {
+	fmt.Println("matched faces:")
+	for _, f := range e.Matched {
+		fmt.Printf("  %s (priority: %d)\n", f.Name, f.Priority)
+	}
+
+	keys := []string{}
+	for k := range e.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Println("attributes:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, k := range keys {
+		o := e.Attributes[k]
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", k, o.Value, strings.Join(o.Faces, ", "))
+	}
+
+	return w.Flush()
+}
+
+func loadComponents(filePath string) (*component.Components, error) {
+	if filePath == "" {
+		return loader.Read(os.Stdin)
+	}
+
+	return loader.LoadFiles(filePath)
+}
@@ -3,11 +3,27 @@ package main
 import (
 	"os"
 
+	"github.com/nihei9/felipe/cmd/felipe/api"
+	"github.com/nihei9/felipe/cmd/felipe/build"
 	"github.com/nihei9/felipe/cmd/felipe/dot"
+	"github.com/nihei9/felipe/cmd/felipe/drift"
+	"github.com/nihei9/felipe/cmd/felipe/faces"
+	"github.com/nihei9/felipe/cmd/felipe/impact"
+	"github.com/nihei9/felipe/cmd/felipe/imports"
+	"github.com/nihei9/felipe/cmd/felipe/lsp"
+	"github.com/nihei9/felipe/cmd/felipe/metrics"
+	"github.com/nihei9/felipe/cmd/felipe/order"
 	"github.com/nihei9/felipe/cmd/felipe/query"
+	"github.com/nihei9/felipe/cmd/felipe/render"
+	"github.com/nihei9/felipe/cmd/felipe/serve"
 	"github.com/spf13/cobra"
 )
 
+// exitCoder is an error that determines the exit status of the command.
+type exitCoder interface {
+	ExitCode() int
+}
+
 func main() {
 	os.Exit(doMain())
 }
@@ -19,6 +35,9 @@ func doMain() int {
 	if err != nil {
 		cmd.SetOutput(os.Stderr)
 		cmd.Println(err)
+		if e, ok := err.(exitCoder); ok {
+			return e.ExitCode()
+		}
 		return 1
 	}
 
@@ -36,6 +55,17 @@ func newCmd() *cobra.Command {
 
 	cmd.AddCommand(query.NewCmd())
 	cmd.AddCommand(dot.NewCmd())
+	cmd.AddCommand(imports.NewCmd())
+	cmd.AddCommand(drift.NewCmd())
+	cmd.AddCommand(metrics.NewCmd())
+	cmd.AddCommand(order.NewCmd())
+	cmd.AddCommand(impact.NewCmd())
+	cmd.AddCommand(faces.NewCmd())
+	cmd.AddCommand(render.NewCmd())
+	cmd.AddCommand(serve.NewCmd())
+	cmd.AddCommand(api.NewCmd())
+	cmd.AddCommand(lsp.NewCmd())
+	cmd.AddCommand(build.NewCmd())
 
 	return cmd
 }
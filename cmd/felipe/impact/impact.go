@@ -0,0 +1,156 @@
+package impact
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nihei9/felipe/cmd/felipe/queryflag"
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/impact"
+	"github.com/nihei9/felipe/render"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagSrcDir   string
+	flagSrcFile  string
+	flagWeights  []string
+	flagOutput   string
+	flagOverlays []string
+)
+
+// distanceColors are fill colors of affected components by their distance.
+// The last one is used for all farther components.
+var distanceColors = []string{
+	"\"#e41a1c\"",
+	"\"#ff7f00\"",
+	"\"#ffd92f\"",
+	"\"#ffffcc\"",
+}
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "impact <component>...",
+		Short: "impact reports components affected by failing components.",
+		Long:  "impact reports components that are transitively affected by failing components with their distance, path and criticality. Components are read from the source directory, the source file or stdin.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  run,
+	}
+	cmd.Flags().StringVarP(&flagSrcDir, "src_dir", "d", "", "directory that contains definition files")
+	cmd.Flags().StringVarP(&flagSrcFile, "src_file", "s", "", "file path that defines components (default: stdin)")
+	cmd.Flags().StringArrayVarP(&flagWeights, "weight", "w", []string{}, "criticality of components having a label (e.g. tier=critical=10)")
+	cmd.Flags().StringVarP(&flagOutput, "output", "o", "text", "output format (text|dot)")
+	cmd.Flags().StringSliceVar(&flagOverlays, "overlay", []string{}, "overlays applied to the definitions; a name refers to <src_dir>/overlays/<name>.yaml")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	switch flagOutput {
+	case "text", "dot":
+	default:
+		return fmt.Errorf("invalid output format; got: %v", flagOutput)
+	}
+
+	weights, err := parseWeights(flagWeights)
+	if err != nil {
+		return err
+	}
+
+	if flagSrcDir != "" && flagSrcFile != "" {
+		return fmt.Errorf("either `--src_dir` or `--src_file` can be specified")
+	}
+	src := flagSrcDir
+	if src == "" {
+		src = flagSrcFile
+	}
+	cs, err := queryflag.LoadComponents(src, flagOverlays)
+	if err != nil {
+		return err
+	}
+
+	ids := []component.ComponentID{}
+	for _, id := range args {
+		ids = append(ids, component.ComponentID(id))
+	}
+	r, err := impact.Analyzer{
+		AllComponents: cs,
+		Weights:       weights,
+	}.Analyze(ids)
+	if err != nil {
+		return err
+	}
+
+	if flagOutput == "dot" {
+		affected := r.Components(cs)
+		return render.WriteDOT(os.Stdout, affected, affected, distanceFaces(r), nil)
+	}
+
+	return writeResult(r)
+}
+
+func parseWeights(ws []string) (impact.Weights, error) {
+	weights := impact.Weights{}
+	for _, w := range ws {
+		f := strings.Split(w, "=")
+		if len(f) != 3 {
+			return nil, fmt.Errorf("weight is malformed; got: %v", w)
+		}
+		k := strings.TrimSpace(f[0])
+		v := strings.TrimSpace(f[1])
+		weight, err := strconv.ParseFloat(strings.TrimSpace(f[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("weight is malformed; got: %v", w)
+		}
+
+		if _, ok := weights[k]; !ok {
+			weights[k] = map[string]float64{}
+		}
+		weights[k][v] = weight
+	}
+
+	return weights, nil
+}
+
+func distanceFaces(r *impact.Result) []*face.Face {
+	fs := []*face.Face{}
+	for d, color := range distanceColors {
+		fs = append(fs, &face.Face{
+			Filter: impact.DistanceFilter{
+				Result:    r,
+				Distance:  d,
+				OrFarther: d == len(distanceColors)-1,
+			},
+			Attributes: map[string]string{
+				"style":     "filled",
+				"fillcolor": color,
+			},
+		})
+	}
+
+	return fs
+}
+
+func writeResult(r *impact.Result) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDISTANCE\tCRITICALITY\tPATH")
+	for _, i := range r.Impacts {
+		path := []string{}
+		for _, id := range i.Path {
+			path = append(path, id.String())
+		}
+		fmt.Fprintf(w, "%s\t%d\t%g\t%s\n", i.ID, i.Distance, i.Criticality, strings.Join(path, " <- "))
+	}
+	err := w.Flush()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("blast radius score: %.2f\n", r.Score)
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package imports
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nihei9/felipe/definitions"
+	"github.com/nihei9/felipe/trace"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	flagTraceFormat string
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "import generates component definitions from external data.",
+		Long:  "import generates component definitions from external data.",
+	}
+	cmd.AddCommand(newTraceCmd())
+
+	return cmd
+}
+
+func newTraceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trace",
+		Short: "trace generates component definitions from exported trace files.",
+		Long:  "trace generates component definitions from exported trace files (OTLP JSON or Jaeger JSON).",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runTrace,
+	}
+	cmd.Flags().StringVarP(&flagTraceFormat, "format", "f", "otlp", "format of the trace files (otlp|jaeger)")
+
+	return cmd
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	var read func(string) ([]*trace.Span, error)
+	switch flagTraceFormat {
+	case "otlp":
+		read = readOTLP
+	case "jaeger":
+		read = readJaeger
+	default:
+		return fmt.Errorf("invalid trace format; got: %v", flagTraceFormat)
+	}
+
+	spans := []*trace.Span{}
+	for _, traceFile := range args {
+		ss, err := read(traceFile)
+		if err != nil {
+			return fmt.Errorf("%s: %v", traceFile, err)
+		}
+		spans = append(spans, ss...)
+	}
+
+	def := definitions.MakeComponentsDefinition(trace.Aggregate(spans))
+	data, err := yaml.Marshal(def)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+func readOTLP(filePath string) ([]*trace.Span, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return trace.ReadOTLP(f)
+}
+
+func readJaeger(filePath string) ([]*trace.Span, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return trace.ReadJaeger(f)
+}
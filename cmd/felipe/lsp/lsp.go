@@ -0,0 +1,24 @@
+package lsp
+
+import (
+	"os"
+
+	"github.com/nihei9/felipe/lsp"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lsp",
+		Short: "lsp runs a language server of definition files over stdio.",
+		Long:  "lsp runs a language server of definition files over stdio. It provides diagnostics, completion of component IDs, go-to-definition, find-references and hover.",
+		Args:  cobra.NoArgs,
+		RunE:  run,
+	}
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	return lsp.Serve(os.Stdin, os.Stdout)
+}
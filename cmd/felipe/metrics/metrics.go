@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/definitions"
+	"github.com/nihei9/felipe/loader"
+	"github.com/nihei9/felipe/metrics"
+	"github.com/nihei9/felipe/query"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	flagSort        string
+	flagReverse     bool
+	flagWriteLabels bool
+	flagOverlays    []string
+)
+
+var lessFuncs = map[string]func(a, b *metrics.Metrics) bool{
+	"id": func(a, b *metrics.Metrics) bool {
+		return a.ID < b.ID
+	},
+	"fan_in": func(a, b *metrics.Metrics) bool {
+		return a.FanIn < b.FanIn
+	},
+	"fan_out": func(a, b *metrics.Metrics) bool {
+		return a.FanOut < b.FanOut
+	},
+	"instability": func(a, b *metrics.Metrics) bool {
+		return a.Instability < b.Instability
+	},
+	"depth": func(a, b *metrics.Metrics) bool {
+		return a.Depth < b.Depth
+	},
+	"betweenness": func(a, b *metrics.Metrics) bool {
+		return a.Betweenness < b.Betweenness
+	},
+}
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "metrics reports graph metrics of components.",
+		Long:  "metrics reports fan-in, fan-out, instability, depth and betweenness centrality of components. With --write_labels, each metric is written as a label with its value and a level label (e.g. metrics.betweenness_level: high|medium|low) that faces can match to highlight hotspots.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  run,
+	}
+	cmd.Flags().StringVarP(&flagSort, "sort", "s", "id", "column used to sort the table (id|fan_in|fan_out|instability|depth|betweenness)")
+	cmd.Flags().BoolVarP(&flagReverse, "reverse", "r", false, "sort the table in descending order")
+	cmd.Flags().BoolVarP(&flagWriteLabels, "write_labels", "w", false, "write components that have metrics and their levels as labels instead of the table")
+	cmd.Flags().StringSliceVar(&flagOverlays, "overlay", []string{}, "overlays applied to the definitions; a name refers to <dir>/overlays/<name>.yaml")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	less, ok := lessFuncs[flagSort]
+	if !ok {
+		return fmt.Errorf("invalid sort column; got: %v", flagSort)
+	}
+
+	cs, err := loader.LoadDir(args[0], flagOverlays...)
+	if err != nil {
+		return err
+	}
+
+	visible, err := query.AllPassFilter{}.Filter(cs)
+	if err != nil {
+		return err
+	}
+
+	ms := metrics.Compute(visible)
+
+	if flagWriteLabels {
+		return writeLabels(visible, ms)
+	}
+
+	sort.SliceStable(ms, func(i, j int) bool {
+		if flagReverse {
+			return less(ms[j], ms[i])
+		}
+		return less(ms[i], ms[j])
+	})
+
+	return writeTable(ms)
+}
+
+func writeTable(ms []*metrics.Metrics) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tFAN_IN\tFAN_OUT\tINSTABILITY\tDEPTH\tBETWEENNESS")
+	for _, m := range ms {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.2f\t%d\t%.2f\n", m.ID, m.FanIn, m.FanOut, m.Instability, m.Depth, m.Betweenness)
+	}
+
+	return w.Flush()
+}
+
+func writeLabels(cs *component.Components, ms []*metrics.Metrics) error {
+	levels := metrics.LevelLabels(ms)
+	for _, m := range ms {
+		c, _ := cs.Get(m.ID)
+		for k, v := range m.Labels() {
+			c.AddLabel(k, v)
+		}
+		for k, v := range levels[m.ID] {
+			c.AddLabel(k, v)
+		}
+	}
+
+	def := definitions.MakeComponentsDefinition(cs)
+	data, err := yaml.Marshal(def)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package order
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/loader"
+	"github.com/nihei9/felipe/order"
+	"github.com/nihei9/felipe/query"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	flagFilter   string
+	flagOutput   string
+	flagOverlays []string
+)
+
+type result struct {
+	Waves []*wave `json:"waves" yaml:"waves"`
+}
+
+type wave struct {
+	Wave       int      `json:"wave" yaml:"wave"`
+	Components []string `json:"components" yaml:"components"`
+}
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "order",
+		Short: "order sorts components into deployment waves.",
+		Long:  "order sorts components topologically and groups them into waves that can be deployed or started in parallel.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  run,
+	}
+	cmd.Flags().StringVarP(&flagFilter, "filter", "f", "", "filter that selects components to be ordered")
+	cmd.Flags().StringVarP(&flagOutput, "output", "o", "text", "output format (text|yaml|json)")
+	cmd.Flags().StringSliceVar(&flagOverlays, "overlay", []string{}, "overlays applied to the definitions; a name refers to <dir>/overlays/<name>.yaml")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	switch flagOutput {
+	case "text", "yaml", "json":
+	default:
+		return fmt.Errorf("invalid output format; got: %v", flagOutput)
+	}
+
+	cs, err := loader.LoadDir(args[0], flagOverlays...)
+	if err != nil {
+		return err
+	}
+
+	waves, err := order.Waves(cs)
+	if err != nil {
+		return err
+	}
+
+	var filter query.Filter = query.AllPassFilter{}
+	if flagFilter != "" {
+		filter, err = query.ParseFilter(flagFilter)
+		if err != nil {
+			return err
+		}
+	}
+	selected, err := filter.Filter(cs)
+	if err != nil {
+		return err
+	}
+
+	return writeResult(order.Select(waves, selected))
+}
+
+func writeResult(waves [][]component.ComponentID) error {
+	r := &result{
+		Waves: []*wave{},
+	}
+	for i, ids := range waves {
+		w := &wave{
+			Wave:       i + 1,
+			Components: []string{},
+		}
+		for _, id := range ids {
+			w.Components = append(w.Components, id.String())
+		}
+		r.Waves = append(r.Waves, w)
+	}
+
+	switch flagOutput {
+	case "yaml":
+		data, err := yaml.Marshal(r)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s", data)
+	case "json":
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+	default:
+		for _, w := range r.Waves {
+			fmt.Printf("%d: %s\n", w.Wave, strings.Join(w.Components, ", "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package outfile
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Write writes output to a file, or to stdout when path is empty. The file is replaced only after the
+// whole output is written, so a failed write leaves the previous file as is and viewers watching the
+// file never read a half-written one.
+func Write(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	tmpPath := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	cerr := f.Close()
+	if err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
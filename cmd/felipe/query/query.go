@@ -1,22 +1,33 @@
 package query
 
 import (
+	"archive/zip"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strconv"
-	"strings"
 
+	"github.com/nihei9/felipe/cmd/felipe/outfile"
+	"github.com/nihei9/felipe/cmd/felipe/queryflag"
 	"github.com/nihei9/felipe/component"
 	"github.com/nihei9/felipe/definitions"
+	"github.com/nihei9/felipe/export"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/loader"
 	"github.com/nihei9/felipe/query"
+	"github.com/nihei9/felipe/watch"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 )
 
 var (
-	flagFilter          string
-	flagComplementation string
+	flagSpec     query.Spec
+	flagOutput   string
+	flagDest     string
+	flagFaceFile string
+	flagOutFile  string
+	flagWatch    bool
+	flagOverlays []string
 )
 
 func NewCmd() *cobra.Command {
@@ -27,128 +38,162 @@ func NewCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE:  run,
 	}
-	cmd.Flags().StringVarP(&flagFilter, "filter", "f", "", "filter used in the query")
-	cmd.Flags().StringVarP(&flagComplementation, "complementation", "c", "", "complementation used in the query")
+	queryflag.Add(cmd, &flagSpec, "f", "c")
+	cmd.Flags().StringVarP(&flagOutput, "output", "o", "yaml", "output format (yaml|csv|tsv|graphml|gexf|cypher)")
+	cmd.Flags().StringVarP(&flagDest, "dest", "d", "", "destination of tables; a directory or a .zip archive (required by csv and tsv)")
+	cmd.Flags().StringVar(&flagFaceFile, "face", "", "file path that defines faces applied to graphml and gexf")
+	cmd.Flags().StringVar(&flagOutFile, "out_file", "", "file path to write the result to except tables (default: stdout)")
+	cmd.Flags().BoolVarP(&flagWatch, "watch", "w", false, "rewrite the output every time the definition or faces files change")
+	cmd.Flags().StringSliceVar(&flagOverlays, "overlay", []string{}, "overlays applied to the definitions; a name refers to <dir>/overlays/<name>.yaml")
 
 	return cmd
 }
 
 func run(cmd *cobra.Command, args []string) error {
-	defFiles, err := listDefinitionFiles(args[0])
+	switch flagOutput {
+	case "yaml", "graphml", "gexf", "cypher":
+	case "csv", "tsv":
+		if flagDest == "" {
+			return fmt.Errorf("`--dest` must be specified when the output format is %v", flagOutput)
+		}
+	default:
+		return fmt.Errorf("invalid output format; got: %v", flagOutput)
+	}
+
+	if !flagWatch {
+		return generate(args[0])
+	}
+
+	paths := []string{args[0]}
+	if flagFaceFile != "" {
+		paths = append(paths, flagFaceFile)
+	}
+	for _, name := range flagOverlays {
+		paths = append(paths, loader.OverlayFile(args[0], name))
+	}
+
+	excludes := []string{}
+	if flagOutFile != "" {
+		excludes = append(excludes, flagOutFile)
+	}
+
+	return watch.Rerun(watch.Poller{
+		Paths:    paths,
+		Excludes: excludes,
+	}, nil, func() error {
+		return generate(args[0])
+	}, func(err error) {
+		fmt.Fprintln(os.Stderr, err)
+	})
+}
+
+func generate(srcDir string) error {
+	cs, err := loader.LoadDir(srcDir, flagOverlays...)
 	if err != nil {
 		return err
 	}
 
-	cs := component.NewComponents()
-	for _, defFile := range defFiles {
-		def, err := readComponentsDefinition(defFile)
-		if err != nil {
-			return err
-		}
-
-		for _, cDef := range def.Components {
-			c := definitions.MakeComponentEntity(cDef)
-			cs.Add(c)
-		}
+	q, err := flagSpec.Query(cs)
+	if err != nil {
+		return err
 	}
-	err = cs.Complement()
+	result, err := q.Do()
 	if err != nil {
 		return err
 	}
 
-	var filter query.Filter
-	if flagFilter != "" {
-		f := strings.Split(flagFilter, "=")
-		if len(f) != 2 {
-			return fmt.Errorf("filter is malformed; got: %v", flagFilter)
-		}
-		k := strings.TrimSpace(f[0])
-		v := strings.TrimSpace(f[1])
+	switch flagOutput {
+	case "csv":
+		return writeTables(result, flagDest, "csv", export.CommaCSV)
+	case "tsv":
+		return writeTables(result, flagDest, "tsv", export.CommaTSV)
+	}
 
-		filter = query.LabelsFilter{
-			Labels: map[string]string{k: v},
+	var fs []*face.Face
+	if flagOutput == "graphml" || flagOutput == "gexf" {
+		fs, err = readFaces()
+		if err != nil {
+			return err
 		}
-	} else {
-		filter = query.AllPassFilter{}
 	}
 
-	var complementer query.Complementer
-	if flagComplementation != "" {
-		f := strings.Split(flagComplementation, "=")
-		if len(f) != 2 {
-			return fmt.Errorf("complementation is malformed; got: %v", flagComplementation)
-		}
-		k := strings.TrimSpace(f[0])
-		v := strings.TrimSpace(f[1])
-
-		switch k {
-		case "dep":
-			depth, err := strconv.Atoi(v)
-			if err != nil {
-				return err
-			}
-			complementer = query.DependenciesComplementer{
-				AllComponents: cs,
-				Depth:         depth,
-			}
-		case "rdep":
-			depth, err := strconv.Atoi(v)
-			if err != nil {
-				return err
-			}
-			complementer = query.ReverseDependenciesComplementer{
-				AllComponents: cs,
-				Depth:         depth,
-			}
+	return outfile.Write(flagOutFile, func(w io.Writer) error {
+		switch flagOutput {
+		case "graphml":
+			return export.WriteGraphML(w, result, fs)
+		case "gexf":
+			return export.WriteGEXF(w, result, fs)
+		case "cypher":
+			return export.WriteCypher(w, result)
 		default:
-			return fmt.Errorf("invalid complementation; got: %v", k)
-		}
-	} else {
-		complementer = query.DependenciesComplementer{
-			AllComponents: cs,
-			Depth:         -1,
+			return writeResult(w, result)
 		}
-	}
+	})
+}
 
-	result, err := query.Query{
-		Components:   cs,
-		Filter:       filter,
-		Complementer: complementer,
-	}.Do()
+func writeResult(w io.Writer, cs *component.Components) error {
+	def := definitions.MakeComponentsDefinition(cs)
+	data, err := yaml.Marshal(def)
 	if err != nil {
 		return err
 	}
 
-	err = writeResult(result)
-	if err != nil {
-		return err
+	_, err = w.Write(data)
+	return err
+}
+
+func writeTables(cs *component.Components, dest string, ext string, comma rune) error {
+	tables := []struct {
+		name  string
+		write func(io.Writer, *component.Components, rune) error
+	}{
+		{name: "nodes." + ext, write: export.WriteNodeTable},
+		{name: "edges." + ext, write: export.WriteEdgeTable},
 	}
 
-	return nil
-}
+	if filepath.Ext(dest) == ".zip" {
+		return outfile.Write(dest, func(f io.Writer) error {
+			zw := zip.NewWriter(f)
+			for _, t := range tables {
+				w, err := zw.Create(t.name)
+				if err != nil {
+					return err
+				}
+				err = t.write(w, cs, comma)
+				if err != nil {
+					return err
+				}
+			}
 
-func listDefinitionFiles(srcDir string) ([]string, error) {
-	return filepath.Glob(filepath.Join(srcDir, "*.yaml"))
-}
+			return zw.Close()
+		})
+	}
 
-func readComponentsDefinition(filePath string) (*definitions.ComponentsDefinition, error) {
-	f, err := os.Open(filePath)
+	err := os.MkdirAll(dest, 0755)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	for _, t := range tables {
+		err := outfile.Write(filepath.Join(dest, t.name), func(w io.Writer) error {
+			return t.write(w, cs, comma)
+		})
+		if err != nil {
+			return err
+		}
 	}
-	defer f.Close()
 
-	return definitions.ReadComponentsDefinition(f)
+	return nil
 }
 
-func writeResult(cs *component.Components) error {
-	def := definitions.MakeComponentsDefinition(cs)
-	data, err := yaml.Marshal(def)
-	if err != nil {
-		return err
+func readFaces() ([]*face.Face, error) {
+	if flagFaceFile == "" {
+		return []*face.Face{}, nil
 	}
 
-	fmt.Printf("%s", data)
+	fs, err := loader.LoadFaces(flagFaceFile)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	return fs.Faces, nil
 }
@@ -0,0 +1,19 @@
+package queryflag
+
+import (
+	"github.com/nihei9/felipe/query"
+	"github.com/spf13/cobra"
+)
+
+// Add adds flags that specify a query to a command. The shorthands of `--filter` and `--complementation`
+// are added only when they are not empty because some commands use `-f` for faces.
+func Add(cmd *cobra.Command, spec *query.Spec, filterShorthand string, complementationShorthand string) {
+	cmd.Flags().StringVarP(&spec.Filter, "filter", filterShorthand, "", "filter used in the query")
+	cmd.Flags().StringVarP(&spec.Complementation, "complementation", complementationShorthand, "", "complementation used in the query; a comma merges complementations and > chains them (e.g. dep=2,rdep=1 or dep=1>rdep=1)")
+	cmd.Flags().StringVar(&spec.Stop, "stop", "", "filter of components at which complementation stops walking")
+	cmd.Flags().StringVar(&spec.Skip, "skip", "", "filter of components complementation walks through without including them")
+	cmd.Flags().BoolVar(&spec.KeepTransitive, "keep_transitive", false, "make components depend indirectly on components reachable through skipped ones")
+	cmd.Flags().BoolVar(&spec.Reduce, "reduce", false, "remove dependencies implied by longer chains of dependencies")
+	cmd.Flags().StringSliceVar(&spec.Preserve, "preserve", []string{}, "relations whose dependencies are never removed by --reduce")
+	cmd.Flags().BoolVar(&spec.BypassHidden, "bypass_hidden", false, "remove hidden components and make their dependents depend indirectly on their dependencies")
+}
@@ -0,0 +1,48 @@
+package queryflag
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/loader"
+)
+
+// Source returns the source of components given by the positional argument or `--src_file`.
+// It is empty when components are read from stdin.
+func Source(args []string, srcFile string) (string, error) {
+	src := srcFile
+	if len(args) > 0 {
+		if srcFile != "" {
+			return "", fmt.Errorf("either a directory or `--src_file` can be specified")
+		}
+		src = args[0]
+	}
+
+	return src, nil
+}
+
+// LoadComponents loads components from a directory, a file or stdin when src is empty. Overlays are
+// looked up in a directory, so they are rejected for the others.
+func LoadComponents(src string, overlays []string) (*component.Components, error) {
+	isDir := false
+	if src != "" {
+		info, err := os.Stat(src)
+		if err != nil {
+			return nil, err
+		}
+		isDir = info.IsDir()
+	}
+	if len(overlays) > 0 && !isDir {
+		return nil, fmt.Errorf("`--overlay` can be specified only with a directory")
+	}
+
+	if src == "" {
+		return loader.Read(os.Stdin)
+	}
+	if isDir {
+		return loader.LoadDir(src, overlays...)
+	}
+
+	return loader.LoadFiles(src)
+}
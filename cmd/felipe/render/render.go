@@ -0,0 +1,79 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nihei9/felipe/cmd/felipe/outfile"
+	"github.com/nihei9/felipe/cmd/felipe/queryflag"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/loader"
+	"github.com/nihei9/felipe/query"
+	"github.com/nihei9/felipe/render"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagSrcFile  string
+	flagFaceFile string
+	flagFormat   string
+	flagOutFile  string
+	flagSpec     query.Spec
+	flagOverlays []string
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render [dir]",
+		Short: "render generates diagrams in a registered format.",
+		Long:  "render generates diagrams in a registered format from components defined in the directory, the source file or stdin. svg and png are laid out by felipe itself, so Graphviz is not needed.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  run,
+	}
+	cmd.Flags().StringVarP(&flagSrcFile, "src_file", "s", "", "file path that defines components (default: stdin)")
+	cmd.Flags().StringVarP(&flagFaceFile, "face", "f", "", "file path that defines faces")
+	cmd.Flags().StringVar(&flagFormat, "format", "svg", fmt.Sprintf("output format (%s)", strings.Join(render.Formats(), "|")))
+	cmd.Flags().StringVarP(&flagOutFile, "out_file", "o", "", "file path to write the diagram to (default: stdout)")
+	queryflag.Add(cmd, &flagSpec, "", "c")
+	cmd.Flags().StringSliceVar(&flagOverlays, "overlay", []string{}, "overlays applied to the definitions; a name refers to <dir>/overlays/<name>.yaml")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	r, err := render.Lookup(flagFormat)
+	if err != nil {
+		return err
+	}
+
+	src, err := queryflag.Source(args, flagSrcFile)
+	if err != nil {
+		return err
+	}
+
+	cs, err := queryflag.LoadComponents(src, flagOverlays)
+	if err != nil {
+		return err
+	}
+	group, err := flagSpec.Group(cs)
+	if err != nil {
+		return err
+	}
+
+	fs := []*face.Face{}
+	var graph *face.Graph
+	if flagFaceFile != "" {
+		faces, err := loader.LoadFaces(flagFaceFile)
+		if err != nil {
+			return err
+		}
+
+		fs = faces.Faces
+		graph = faces.Graph
+	}
+
+	return outfile.Write(flagOutFile, func(w io.Writer) error {
+		return r.Render(w, group, cs, fs, graph)
+	})
+}
@@ -0,0 +1,60 @@
+package serve
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/nihei9/felipe/server"
+	"github.com/nihei9/felipe/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagFaceFile string
+	flagAddr     string
+	flagInterval time.Duration
+	flagOverlays []string
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve <dir>",
+		Short: "serve serves diagrams and a JSON API of components over HTTP.",
+		Long:  "serve serves diagrams and a JSON API of components over HTTP. It reloads definitions in the directory when they change, and browsers refresh diagrams automatically.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  run,
+	}
+	cmd.Flags().StringVarP(&flagFaceFile, "face", "f", "", "file path that defines faces")
+	cmd.Flags().StringVarP(&flagAddr, "addr", "a", "localhost:8080", "address to listen on")
+	cmd.Flags().DurationVar(&flagInterval, "interval", watch.DefaultInterval, "interval of checking changes of definitions")
+	cmd.Flags().StringSliceVar(&flagOverlays, "overlay", []string{}, "overlays applied to the definitions; a name refers to <dir>/overlays/<name>.yaml")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	s := server.New(args[0], flagFaceFile)
+	s.Overlays = flagOverlays
+	err := s.Reload()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	go func() {
+		err := s.Watch(nil, flagInterval, func(err error) {
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return
+			}
+			fmt.Fprintln(os.Stderr, "reloaded definitions")
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "serving on http://%s\n", flagAddr)
+	return http.ListenAndServe(flagAddr, s)
+}
@@ -87,6 +87,10 @@ func (cs *Components) Complement() error {
 
 type Relation struct {
 	Description string
+	Attributes  map[string]string
+
+	// Indirect is true when the relation stands for a chain of dependencies through elided components.
+	Indirect bool
 }
 
 type complementStatus string
@@ -118,6 +122,26 @@ func NewComponent(baseID ComponentID, id ComponentID) *Component {
 	}
 }
 
+// Clone returns a copy of the component that can be modified without affecting the original.
+func (c *Component) Clone() *Component {
+	clone := &Component{
+		ID:               c.ID,
+		Labels:           map[string]string{},
+		Dependencies:     map[ComponentID]*Relation{},
+		baseID:           c.baseID,
+		hidden:           c.hidden,
+		complementStatus: c.complementStatus,
+	}
+	for k, v := range c.Labels {
+		clone.Labels[k] = v
+	}
+	for dep, rel := range c.Dependencies {
+		clone.Dependencies[dep] = rel
+	}
+
+	return clone
+}
+
 func (c *Component) AddLabel(key string, value string) {
 	c.Labels[key] = value
 }
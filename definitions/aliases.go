@@ -0,0 +1,88 @@
+package definitions
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	DefinitionKindAliases = "aliases"
+)
+
+func ReadAliasesDefinition(r io.Reader) (*AliasesDefinition, error) {
+	def := &AliasesDefinition{}
+	err := yaml.NewDecoder(r).Decode(def)
+	if err != nil {
+		return nil, err
+	}
+
+	err = def.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return def, nil
+}
+
+type AliasesDefinition struct {
+	Version string   `yaml:"version"`
+	Kind    string   `yaml:"kind"`
+	Aliases []*Alias `yaml:"aliases"`
+}
+
+func (def *AliasesDefinition) validate() error {
+	if def.Version == "" {
+		return errorVersionIsMissing
+	}
+	if def.Kind == "" {
+		return errorKindIsMissing
+	}
+	if def.Kind != DefinitionKindAliases {
+		return errorKindIsNotAliases
+	}
+	if len(def.Aliases) <= 0 {
+		return errorAliasesHasNoAlias
+	}
+	known := map[string]bool{}
+	for _, a := range def.Aliases {
+		if a == nil {
+			return errorAliasesHasEmptyAlias
+		}
+
+		err := a.validate()
+		if err != nil {
+			return err
+		}
+
+		for _, name := range a.Names {
+			if known[name] {
+				return errorAliasNameIsDuplicated
+			}
+			known[name] = true
+		}
+	}
+
+	return nil
+}
+
+type Alias struct {
+	ID    string   `yaml:"id"`
+	Names []string `yaml:"names"`
+}
+
+func (a *Alias) validate() error {
+	if a.ID == "" {
+		return errorAliasIDIsMissing
+	}
+	if len(a.Names) <= 0 {
+		return errorAliasHasNoName
+	}
+	for _, name := range a.Names {
+		if name == "" {
+			return errorAliasHasEmptyName
+		}
+	}
+
+	return nil
+}
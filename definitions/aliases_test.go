@@ -0,0 +1,132 @@
+package definitions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAliasesDefinition(t *testing.T) {
+	tests := []struct {
+		caption string
+		data    string
+		err     error
+	}{
+		{
+			caption: "`aliases` has an alias",
+			data: `
+version: 1
+kind: aliases
+aliases:
+- id: c1
+  names:
+  - c1-svc
+`,
+		},
+		{
+			caption: "`aliases` has some aliases",
+			data: `
+version: 1
+kind: aliases
+aliases:
+- id: c1
+  names:
+  - c1-svc
+  - c1-api
+- id: c2
+  names:
+  - c2-svc
+`,
+		},
+		{
+			caption: "`kind` is not `aliases`",
+			data: `
+version: 1
+kind: foo
+aliases:
+- id: c1
+  names:
+  - c1-svc
+`,
+			err: errorKindIsNotAliases,
+		},
+		{
+			caption: "`aliases` has no alias",
+			data: `
+version: 1
+kind: aliases
+aliases:
+`,
+			err: errorAliasesHasNoAlias,
+		},
+		{
+			caption: "`aliases[]` includes an empty alias",
+			data: `
+version: 1
+kind: aliases
+aliases:
+- id: c1
+  names:
+  - c1-svc
+-
+`,
+			err: errorAliasesHasEmptyAlias,
+		},
+		{
+			caption: "`aliases[].id` is not specified",
+			data: `
+version: 1
+kind: aliases
+aliases:
+- names:
+  - c1-svc
+`,
+			err: errorAliasIDIsMissing,
+		},
+		{
+			caption: "`aliases[].names` has no name",
+			data: `
+version: 1
+kind: aliases
+aliases:
+- id: c1
+`,
+			err: errorAliasHasNoName,
+		},
+		{
+			caption: "`aliases[].names[]` includes an empty name",
+			data: `
+version: 1
+kind: aliases
+aliases:
+- id: c1
+  names:
+  - c1-svc
+  - ""
+`,
+			err: errorAliasHasEmptyName,
+		},
+		{
+			caption: "`aliases[].names[]` is duplicated",
+			data: `
+version: 1
+kind: aliases
+aliases:
+- id: c1
+  names:
+  - svc
+- id: c2
+  names:
+  - svc
+`,
+			err: errorAliasNameIsDuplicated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			_, err := ReadAliasesDefinition(strings.NewReader(tt.data))
+			if err != tt.err {
+				t.Error(err)
+			}
+		})
+	}
+}
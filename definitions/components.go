@@ -27,9 +27,9 @@ func ReadComponentsDefinition(r io.Reader) (*ComponentsDefinition, error) {
 }
 
 type ComponentsDefinition struct {
-	Version    string       `yaml:"version"`
-	Kind       string       `yaml:"kind"`
-	Components []*Component `yaml:"components"`
+	Version    string       `yaml:"version" json:"version"`
+	Kind       string       `yaml:"kind" json:"kind"`
+	Components []*Component `yaml:"components" json:"components"`
 }
 
 func (def *ComponentsDefinition) validate() error {
@@ -60,11 +60,11 @@ func (def *ComponentsDefinition) validate() error {
 }
 
 type Component struct {
-	ID           string                `yaml:"id"`
-	Base         string                `yaml:"base,omitempty"`
-	Hide         bool                  `yaml:"hide,omitempty"`
-	Labels       map[string]string     `yaml:"labels,omitempty"`
-	Dependencies []*DependentComponent `yaml:"dependencies,omitempty"`
+	ID           string                `yaml:"id" json:"id"`
+	Base         string                `yaml:"base,omitempty" json:"base,omitempty"`
+	Hide         bool                  `yaml:"hide,omitempty" json:"hide,omitempty"`
+	Labels       map[string]string     `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Dependencies []*DependentComponent `yaml:"dependencies,omitempty" json:"dependencies,omitempty"`
 }
 
 func (c *Component) UnmarshalYAML(unmarshal func(interface{}) error) error {
@@ -127,8 +127,10 @@ func (c *Component) validate() error {
 }
 
 type DependentComponent struct {
-	ID       string `yaml:"id"`
-	Relation string `yaml:"relation"`
+	ID         string            `yaml:"id" json:"id"`
+	Relation   string            `yaml:"relation" json:"relation"`
+	Attributes map[string]string `yaml:"attributes,omitempty" json:"attributes,omitempty"`
+	Indirect   bool              `yaml:"indirect,omitempty" json:"indirect,omitempty"`
 }
 
 func (dc *DependentComponent) validate() error {
@@ -1,7 +1,12 @@
 package definitions
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/query"
 )
 
 func MakeComponentsDefinition(cs *component.Components) *ComponentsDefinition {
@@ -11,10 +16,15 @@ func MakeComponentsDefinition(cs *component.Components) *ComponentsDefinition {
 		deps := []*DependentComponent{}
 		for dep, rel := range c.Dependencies {
 			deps = append(deps, &DependentComponent{
-				ID:       dep.String(),
-				Relation: rel.Description,
+				ID:         dep.String(),
+				Relation:   rel.Description,
+				Attributes: rel.Attributes,
+				Indirect:   rel.Indirect,
 			})
 		}
+		sort.Slice(deps, func(i, j int) bool {
+			return deps[i].ID < deps[j].ID
+		})
 
 		components = append(components, &Component{
 			ID:           c.ID.String(),
@@ -41,6 +51,8 @@ func MakeComponentEntity(def *Component) *component.Component {
 	for _, dDef := range def.Dependencies {
 		rel := &component.Relation{
 			Description: dDef.Relation,
+			Attributes:  dDef.Attributes,
+			Indirect:    dDef.Indirect,
 		}
 		c.DependOn(component.ComponentID(dDef.ID), rel)
 	}
@@ -50,3 +62,75 @@ func MakeComponentEntity(def *Component) *component.Component {
 
 	return c
 }
+
+func MakeAliases(def *AliasesDefinition) map[component.ComponentID]component.ComponentID {
+	aliases := map[component.ComponentID]component.ComponentID{}
+	for _, a := range def.Aliases {
+		for _, name := range a.Names {
+			aliases[component.ComponentID(name)] = component.ComponentID(a.ID)
+		}
+	}
+
+	return aliases
+}
+
+func MakeFaceEntities(def *FacesDefinition) []*face.Face {
+	fs := []*face.Face{}
+	if def.Default != nil {
+		fs = append(fs, &face.Face{
+			Name:       "default",
+			Attributes: def.Default.Attributes,
+			Default:    true,
+		})
+	}
+	for i, fDef := range def.Faces {
+		f := MakeFaceEntity(fDef)
+		if f.Name == "" {
+			f.Name = fmt.Sprintf("faces[%d]", i)
+		}
+		fs = append(fs, f)
+	}
+
+	return fs
+}
+
+func MakeGraphEntity(def *FacesDefinition) *face.Graph {
+	g := &face.Graph{}
+	if def.Graph != nil {
+		g.Attributes = def.Graph.Attributes
+		g.Node = def.Graph.Node
+		g.Edge = def.Graph.Edge
+	}
+	if def.Legend != nil {
+		g.Legend = def.Legend.Label
+		if g.Legend == "" {
+			g.Legend = "Legend"
+		}
+	}
+
+	return g
+}
+
+func MakeFaceEntity(def *Face) *face.Face {
+	appendKeys := map[string]bool{}
+	for _, k := range def.Append {
+		appendKeys[k] = true
+	}
+
+	return &face.Face{
+		Name: def.Name,
+		Filter: query.LabelsFilter{
+			Labels: def.Targets.MatchLabels,
+		},
+		Attributes: def.Attributes,
+		Priority:   def.Priority,
+		Append:     appendKeys,
+	}
+}
+
+func MakeQuerySpec(v *View) query.Spec {
+	return query.Spec{
+		Filter:          v.Filter,
+		Complementation: v.Complementation,
+	}
+}
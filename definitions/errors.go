@@ -3,20 +3,48 @@ package definitions
 import "errors"
 
 var (
-	errorVersionIsMissing                   = errors.New("`version` must be specified")
-	errorKindIsMissing                      = errors.New("`kind` must be specified")
-	errorKindIsNotComponents                = errors.New("`kind` must be `components`")
-	errorKindIsNotFaces                     = errors.New("`kind` must be `faces`")
-	errorComponentsHasNoComponent           = errors.New("`components` must contain at least one content")
-	errorComponentsHasEmptyComponent        = errors.New("`components[]` includes empty components")
-	errorComponentIDIsMissing               = errors.New("`components[].id` must be specified")
-	errorComponentHasEmptyDependency        = errors.New("`dependencies[]` includes empty components")
-	errorDependencyIDIsMissing              = errors.New("`dependencies[].id` must be specified")
-	errorFacesHasNoFace                     = errors.New("`faces` must contain at least one face")
-	errorFacesHasEmptyFace                  = errors.New("`faces[]` includes empty faces")
-	errorFaceTargetIsMissing                = errors.New("`faces[].targets` is not specified")
-	errorFaceTargetIsEmpty                  = errors.New("`faces[].targets` is empty")
-	errorFaceMatchLabelsTargetHasEmptyEntry = errors.New("`faces[].targets.match_labels[]` includes empty entries")
-	errorFaceAttributesHasNoAttribute       = errors.New("`faces[].attributes[]` must contain at least one attribute")
-	errorFaceAttributesHasEmptyAttribute    = errors.New("`faces[].attributes[]` includes empty attributes")
+	errorVersionIsMissing                       = errors.New("`version` must be specified")
+	errorKindIsMissing                          = errors.New("`kind` must be specified")
+	errorKindIsNotComponents                    = errors.New("`kind` must be `components`")
+	errorKindIsNotFaces                         = errors.New("`kind` must be `faces`")
+	errorKindIsNotAliases                       = errors.New("`kind` must be `aliases`")
+	errorKindIsNotViews                         = errors.New("`kind` must be `views`")
+	errorKindIsNotOverlay                       = errors.New("`kind` must be `overlay`")
+	errorComponentsHasNoComponent               = errors.New("`components` must contain at least one content")
+	errorComponentsHasEmptyComponent            = errors.New("`components[]` includes empty components")
+	errorComponentIDIsMissing                   = errors.New("`components[].id` must be specified")
+	errorComponentHasEmptyDependency            = errors.New("`dependencies[]` includes empty components")
+	errorDependencyIDIsMissing                  = errors.New("`dependencies[].id` must be specified")
+	errorFacesHasNoFace                         = errors.New("`faces` must contain at least one face")
+	errorFacesHasEmptyFace                      = errors.New("`faces[]` includes empty faces")
+	errorFaceTargetIsMissing                    = errors.New("`faces[].targets` is not specified")
+	errorFaceTargetIsEmpty                      = errors.New("`faces[].targets` is empty")
+	errorFaceMatchLabelsTargetHasEmptyEntry     = errors.New("`faces[].targets.match_labels[]` includes empty entries")
+	errorFaceAttributesHasNoAttribute           = errors.New("`faces[].attributes[]` must contain at least one attribute")
+	errorFaceAttributesHasEmptyAttribute        = errors.New("`faces[].attributes[]` includes empty attributes")
+	errorFaceAppendHasUndefinedAttribute        = errors.New("`faces[].append[]` must be keys of `faces[].attributes`")
+	errorFaceNameIsDuplicated                   = errors.New("`faces[].name` must be unique")
+	errorDefaultFaceAttributesHasNoAttribute    = errors.New("`default.attributes[]` must contain at least one attribute")
+	errorDefaultFaceAttributesHasEmptyAttribute = errors.New("`default.attributes[]` includes empty attributes")
+	errorGraphAttributesHasEmptyAttribute       = errors.New("`graph.attributes[]` includes empty attributes")
+	errorGraphNodeHasEmptyAttribute             = errors.New("`graph.node[]` includes empty attributes")
+	errorGraphEdgeHasEmptyAttribute             = errors.New("`graph.edge[]` includes empty attributes")
+	errorAliasesHasNoAlias                      = errors.New("`aliases` must contain at least one alias")
+	errorAliasesHasEmptyAlias                   = errors.New("`aliases[]` includes empty aliases")
+	errorAliasIDIsMissing                       = errors.New("`aliases[].id` must be specified")
+	errorAliasHasNoName                         = errors.New("`aliases[].names` must contain at least one name")
+	errorAliasHasEmptyName                      = errors.New("`aliases[].names[]` includes empty names")
+	errorAliasNameIsDuplicated                  = errors.New("`aliases[].names[]` must be unique across all aliases")
+	errorViewsHasNoView                         = errors.New("`views` must contain at least one view")
+	errorViewsHasEmptyView                      = errors.New("`views[]` includes empty views")
+	errorViewNameIsMissing                      = errors.New("`views[].name` must be specified")
+	errorViewNameIsInvalid                      = errors.New("`views[].name` must not contain path separators")
+	errorViewNameIsDuplicated                   = errors.New("`views[].name` must be unique")
+	errorViewGroupByRequiresDOT                 = errors.New("`views[].group_by` requires `views[].format` to be `dot`")
+	errorOverlayHasNoChange                     = errors.New("`remove`, `add` or `patch` must contain at least one change")
+	errorOverlayRemoveHasEmptyID                = errors.New("`remove[]` includes empty IDs")
+	errorOverlayAddHasEmptyComponent            = errors.New("`add[]` includes empty components")
+	errorOverlayPatchHasEmptyPatch              = errors.New("`patch[]` includes empty patches")
+	errorPatchIDIsMissing                       = errors.New("`patch[].id` must be specified")
+	errorPatchLabelsHasEmptyLabel               = errors.New("`patch[].labels[]` includes empty labels")
 )
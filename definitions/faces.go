@@ -26,9 +26,12 @@ func ReadFacesDefinition(r io.Reader) (*FacesDefinition, error) {
 }
 
 type FacesDefinition struct {
-	Version string  `yaml:"version"`
-	Kind    string  `yaml:"kind"`
-	Faces   []*Face `yaml:"faces"`
+	Version string       `yaml:"version"`
+	Kind    string       `yaml:"kind"`
+	Graph   *Graph       `yaml:"graph"`
+	Legend  *Legend      `yaml:"legend"`
+	Default *DefaultFace `yaml:"default"`
+	Faces   []*Face      `yaml:"faces"`
 }
 
 func (def *FacesDefinition) validate() error {
@@ -41,9 +44,22 @@ func (def *FacesDefinition) validate() error {
 	if def.Kind != DefinitionKindFaces {
 		return errorKindIsNotFaces
 	}
-	if len(def.Faces) <= 0 {
+	if def.Graph != nil {
+		err := def.Graph.validate()
+		if err != nil {
+			return err
+		}
+	}
+	if def.Default != nil {
+		err := def.Default.validate()
+		if err != nil {
+			return err
+		}
+	}
+	if len(def.Faces) <= 0 && def.Default == nil && def.Graph == nil {
 		return errorFacesHasNoFace
 	}
+	known := map[string]bool{}
 	for _, f := range def.Faces {
 		if f == nil {
 			return errorFacesHasEmptyFace
@@ -53,14 +69,74 @@ func (def *FacesDefinition) validate() error {
 		if err != nil {
 			return err
 		}
+
+		// Names are optional, but `faces explain` tells faces apart by them.
+		if f.Name == "" {
+			continue
+		}
+		if known[f.Name] {
+			return errorFaceNameIsDuplicated
+		}
+		known[f.Name] = true
+	}
+
+	return nil
+}
+
+// Graph holds attributes of a whole graph and default attributes of nodes and edges.
+type Graph struct {
+	Attributes map[string]string `yaml:"attributes"`
+	Node       map[string]string `yaml:"node"`
+	Edge       map[string]string `yaml:"edge"`
+}
+
+func (g *Graph) validate() error {
+	for k := range g.Attributes {
+		if k == "" {
+			return errorGraphAttributesHasEmptyAttribute
+		}
+	}
+	for k := range g.Node {
+		if k == "" {
+			return errorGraphNodeHasEmptyAttribute
+		}
+	}
+	for k := range g.Edge {
+		if k == "" {
+			return errorGraphEdgeHasEmptyAttribute
+		}
+	}
+
+	return nil
+}
+
+type Legend struct {
+	Label string `yaml:"label"`
+}
+
+type DefaultFace struct {
+	Attributes map[string]string `yaml:"attributes"`
+}
+
+func (f *DefaultFace) validate() error {
+	if len(f.Attributes) <= 0 {
+		return errorDefaultFaceAttributesHasNoAttribute
+	}
+	for k := range f.Attributes {
+		if k == "" {
+			return errorDefaultFaceAttributesHasEmptyAttribute
+		}
 	}
 
 	return nil
 }
 
 type Face struct {
+	Name       string            `yaml:"name"`
+	Priority   int               `yaml:"priority"`
 	Targets    *Targets          `yaml:"targets"`
 	Attributes map[string]string `yaml:"attributes"`
+	Append     []string          `yaml:"append"`
 }
 
 func (f *Face) validate() error {
@@ -79,6 +155,11 @@ func (f *Face) validate() error {
 			return errorFaceAttributesHasEmptyAttribute
 		}
 	}
+	for _, k := range f.Append {
+		if _, ok := f.Attributes[k]; !ok {
+			return errorFaceAppendHasUndefinedAttribute
+		}
+	}
 
 	return nil
 }
@@ -37,6 +37,55 @@ faces:
     fontcolor: white
     fillcolor: black
     style: filled
+`,
+		},
+		{
+			caption: "`faces` has only a default face",
+			data: `
+version: 1
+kind: faces
+default:
+  attributes:
+    shape: box
+`,
+		},
+		{
+			caption: "`faces` has a face with a name, a priority and appended attributes",
+			data: `
+version: 1
+kind: faces
+faces:
+- name: foo
+  priority: 10
+  targets:
+    match_labels:
+      l1: foo
+  attributes:
+    style: filled
+  append:
+  - style
+`,
+		},
+		{
+			caption: "`faces` has graph attributes and a legend",
+			data: `
+version: 1
+kind: faces
+graph:
+  attributes:
+    rankdir: TB
+  node:
+    fontname: Helvetica
+  edge:
+    color: gray
+legend:
+  label: Styles
+faces:
+- targets:
+    match_labels:
+      l1: foo
+  attributes:
+    fontcolor: red
 `,
 		},
 		{
@@ -275,6 +324,115 @@ faces:
 `,
 			err: errorFaceAttributesHasEmptyAttribute,
 		},
+		{
+			caption: "`faces[].append[]` includes an undefined attribute",
+			data: `
+version: 1
+kind: faces
+faces:
+- targets:
+    match_labels:
+      l1: foo
+  attributes:
+    fontcolor: red
+  append:
+  - style
+`,
+			err: errorFaceAppendHasUndefinedAttribute,
+		},
+		{
+			caption: "`faces[].name` is duplicated",
+			data: `
+version: 1
+kind: faces
+faces:
+- name: db
+  targets:
+    match_labels:
+      l1: foo
+  attributes:
+    fontcolor: red
+- name: db
+  targets:
+    match_labels:
+      l1: bar
+  attributes:
+    fontcolor: blue
+`,
+			err: errorFaceNameIsDuplicated,
+		},
+		{
+			caption: "`faces[].name` can be omitted in several faces",
+			data: `
+version: 1
+kind: faces
+faces:
+- targets:
+    match_labels:
+      l1: foo
+  attributes:
+    fontcolor: red
+- targets:
+    match_labels:
+      l1: bar
+  attributes:
+    fontcolor: blue
+`,
+		},
+		{
+			caption: "`default.attributes[]` has no attribute",
+			data: `
+version: 1
+kind: faces
+default:
+  attributes:
+`,
+			err: errorDefaultFaceAttributesHasNoAttribute,
+		},
+		{
+			caption: "`default.attributes[]` includes an empty attribute",
+			data: `
+version: 1
+kind: faces
+default:
+  attributes:
+    "": foo
+`,
+			err: errorDefaultFaceAttributesHasEmptyAttribute,
+		},
+		{
+			caption: "`graph.attributes[]` includes an empty attribute",
+			data: `
+version: 1
+kind: faces
+graph:
+  attributes:
+    "": TB
+`,
+			err: errorGraphAttributesHasEmptyAttribute,
+		},
+		{
+			caption: "`graph.node[]` includes an empty attribute",
+			data: `
+version: 1
+kind: faces
+graph:
+  node:
+    "": box
+`,
+			err: errorGraphNodeHasEmptyAttribute,
+		},
+		{
+			caption: "`graph.edge[]` includes an empty attribute",
+			data: `
+version: 1
+kind: faces
+graph:
+  edge:
+    "": gray
+`,
+			err: errorGraphEdgeHasEmptyAttribute,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.caption, func(t *testing.T) {
@@ -0,0 +1,225 @@
+package definitions
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	DefinitionKindOverlay = "overlay"
+)
+
+func ReadOverlayDefinition(r io.Reader) (*OverlayDefinition, error) {
+	def := &OverlayDefinition{}
+	err := yaml.NewDecoder(r).Decode(def)
+	if err != nil {
+		return nil, err
+	}
+
+	err = def.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return def, nil
+}
+
+// OverlayDefinition changes components defined by a base set of definitions, such as components that
+// differ between environments. Components are removed, added and then patched in this order.
+type OverlayDefinition struct {
+	Version string       `yaml:"version"`
+	Kind    string       `yaml:"kind"`
+	Remove  []string     `yaml:"remove"`
+	Add     []*Component `yaml:"add"`
+	Patch   []*Patch     `yaml:"patch"`
+}
+
+func (def *OverlayDefinition) validate() error {
+	if def.Version == "" {
+		return errorVersionIsMissing
+	}
+	if def.Kind == "" {
+		return errorKindIsMissing
+	}
+	if def.Kind != DefinitionKindOverlay {
+		return errorKindIsNotOverlay
+	}
+	if len(def.Remove) <= 0 && len(def.Add) <= 0 && len(def.Patch) <= 0 {
+		return errorOverlayHasNoChange
+	}
+	for _, id := range def.Remove {
+		if id == "" {
+			return errorOverlayRemoveHasEmptyID
+		}
+	}
+	for _, c := range def.Add {
+		if c == nil {
+			return errorOverlayAddHasEmptyComponent
+		}
+
+		err := c.validate()
+		if err != nil {
+			return err
+		}
+	}
+	for _, p := range def.Patch {
+		if p == nil {
+			return errorOverlayPatchHasEmptyPatch
+		}
+
+		err := p.validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Patch changes a component. Labels are set and dependencies are added; a dependency on a component
+// that the component already depends on replaces the existing one.
+type Patch struct {
+	ID                 string                `yaml:"id"`
+	Hide               *bool                 `yaml:"hide"`
+	Labels             map[string]string     `yaml:"labels"`
+	RemoveLabels       []string              `yaml:"remove_labels"`
+	Dependencies       []*DependentComponent `yaml:"dependencies"`
+	RemoveDependencies []string              `yaml:"remove_dependencies"`
+}
+
+func (p *Patch) validate() error {
+	if p.ID == "" {
+		return errorPatchIDIsMissing
+	}
+	for k := range p.Labels {
+		if k == "" {
+			return errorPatchLabelsHasEmptyLabel
+		}
+	}
+	for _, dc := range p.Dependencies {
+		if dc == nil {
+			return errorComponentHasEmptyDependency
+		}
+
+		err := dc.validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ApplyOverlay applies an overlay to components and returns the changed components. Components passed
+// to it are not modified. Removing a component also removes dependencies on it.
+func ApplyOverlay(cs []*Component, o *OverlayDefinition) ([]*Component, error) {
+	result := []*Component{}
+	index := map[string]int{}
+	for _, c := range cs {
+		index[c.ID] = len(result)
+		result = append(result, c)
+	}
+
+	removed := map[string]bool{}
+	for _, id := range o.Remove {
+		if _, ok := index[id]; !ok {
+			return nil, fmt.Errorf("`remove[]` includes an undefined component `%s`", id)
+		}
+		removed[id] = true
+	}
+	if len(removed) > 0 {
+		remains := []*Component{}
+		index = map[string]int{}
+		for _, c := range result {
+			if removed[c.ID] {
+				continue
+			}
+			c = copyComponent(c)
+			deps := []*DependentComponent{}
+			for _, dc := range c.Dependencies {
+				if !removed[dc.ID] {
+					deps = append(deps, dc)
+				}
+			}
+			c.Dependencies = deps
+			index[c.ID] = len(remains)
+			remains = append(remains, c)
+		}
+		result = remains
+	}
+
+	for _, c := range o.Add {
+		if _, ok := index[c.ID]; ok {
+			return nil, fmt.Errorf("`add[]` includes a component `%s` that is already defined; patch it instead", c.ID)
+		}
+		index[c.ID] = len(result)
+		result = append(result, copyComponent(c))
+	}
+
+	for _, p := range o.Patch {
+		i, ok := index[p.ID]
+		if !ok {
+			return nil, fmt.Errorf("`patch[]` includes an undefined component `%s`", p.ID)
+		}
+		c, err := p.apply(copyComponent(result[i]))
+		if err != nil {
+			return nil, err
+		}
+		result[i] = c
+	}
+
+	return result, nil
+}
+
+func (p *Patch) apply(c *Component) (*Component, error) {
+	if p.Hide != nil {
+		c.Hide = *p.Hide
+	}
+	for k, v := range p.Labels {
+		c.Labels[k] = v
+	}
+	for _, k := range p.RemoveLabels {
+		delete(c.Labels, k)
+	}
+	for _, dc := range p.Dependencies {
+		replaced := false
+		for i, cur := range c.Dependencies {
+			if cur.ID == dc.ID {
+				c.Dependencies[i] = dc
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			c.Dependencies = append(c.Dependencies, dc)
+		}
+	}
+	for _, id := range p.RemoveDependencies {
+		deps := []*DependentComponent{}
+		for _, dc := range c.Dependencies {
+			if dc.ID != id {
+				deps = append(deps, dc)
+			}
+		}
+		if len(deps) == len(c.Dependencies) {
+			return nil, fmt.Errorf("`patch[].remove_dependencies[]` includes `%s` that the component `%s` does not depend on", id, c.ID)
+		}
+		c.Dependencies = deps
+	}
+
+	return c, nil
+}
+
+// copyComponent copies a component so that changing its labels and dependencies does not change the original.
+func copyComponent(c *Component) *Component {
+	cp := *c
+	cp.Labels = map[string]string{}
+	for k, v := range c.Labels {
+		cp.Labels[k] = v
+	}
+	cp.Dependencies = append([]*DependentComponent{}, c.Dependencies...)
+
+	return &cp
+}
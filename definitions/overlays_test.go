@@ -0,0 +1,225 @@
+package definitions
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOverlayDefinition(t *testing.T) {
+	tests := []struct {
+		caption string
+		data    string
+		err     error
+	}{
+		{
+			caption: "an overlay has all kinds of changes",
+			data: `
+version: 1
+kind: overlay
+remove:
+- mock-payments
+add:
+- id: cdn
+  dependencies:
+  - id: web
+patch:
+- id: web
+  labels:
+    replicas: "3"
+  remove_labels:
+  - debug
+  dependencies:
+  - id: payments
+  remove_dependencies:
+  - mock-payments
+`,
+		},
+		{
+			caption: "`kind` is not `overlay`",
+			data: `
+version: 1
+kind: components
+remove:
+- c1
+`,
+			err: errorKindIsNotOverlay,
+		},
+		{
+			caption: "an overlay has no change",
+			data: `
+version: 1
+kind: overlay
+`,
+			err: errorOverlayHasNoChange,
+		},
+		{
+			caption: "`remove[]` includes an empty ID",
+			data: `
+version: 1
+kind: overlay
+remove:
+- ""
+`,
+			err: errorOverlayRemoveHasEmptyID,
+		},
+		{
+			caption: "`add[]` includes a component without an ID",
+			data: `
+version: 1
+kind: overlay
+add:
+- labels:
+    tier: cdn
+`,
+			err: errorComponentIDIsMissing,
+		},
+		{
+			caption: "`patch[]` includes an empty patch",
+			data: `
+version: 1
+kind: overlay
+patch:
+-
+`,
+			err: errorOverlayPatchHasEmptyPatch,
+		},
+		{
+			caption: "`patch[].id` is not specified",
+			data: `
+version: 1
+kind: overlay
+patch:
+- labels:
+    replicas: "3"
+`,
+			err: errorPatchIDIsMissing,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			_, err := ReadOverlayDefinition(strings.NewReader(tt.data))
+			if err != tt.err {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestApplyOverlay(t *testing.T) {
+	base := []*Component{
+		{
+			ID:     "web",
+			Labels: map[string]string{"debug": "true"},
+			Dependencies: []*DependentComponent{
+				{ID: "mock-payments"},
+				{ID: "db", Relation: "reads"},
+			},
+		},
+		{ID: "mock-payments", Labels: map[string]string{}},
+		{ID: "db", Labels: map[string]string{}},
+	}
+	hide := true
+
+	tests := []struct {
+		caption  string
+		overlay  *OverlayDefinition
+		expected []*Component
+		err      bool
+	}{
+		{
+			caption: "remove a component and dependencies on it",
+			overlay: &OverlayDefinition{
+				Remove: []string{"mock-payments"},
+			},
+			expected: []*Component{
+				{
+					ID:     "web",
+					Labels: map[string]string{"debug": "true"},
+					Dependencies: []*DependentComponent{
+						{ID: "db", Relation: "reads"},
+					},
+				},
+				{ID: "db", Labels: map[string]string{}, Dependencies: []*DependentComponent{}},
+			},
+		},
+		{
+			caption: "add and patch components",
+			overlay: &OverlayDefinition{
+				Add: []*Component{
+					{ID: "cdn", Dependencies: []*DependentComponent{{ID: "web"}}},
+				},
+				Patch: []*Patch{
+					{
+						ID:                 "web",
+						Labels:             map[string]string{"replicas": "3"},
+						RemoveLabels:       []string{"debug"},
+						Dependencies:       []*DependentComponent{{ID: "db", Relation: "writes"}},
+						RemoveDependencies: []string{"mock-payments"},
+					},
+					{ID: "mock-payments", Hide: &hide},
+				},
+			},
+			expected: []*Component{
+				{
+					ID:     "web",
+					Labels: map[string]string{"replicas": "3"},
+					Dependencies: []*DependentComponent{
+						{ID: "db", Relation: "writes"},
+					},
+				},
+				{ID: "mock-payments", Hide: true, Labels: map[string]string{}, Dependencies: []*DependentComponent{}},
+				{ID: "db", Labels: map[string]string{}},
+				{ID: "cdn", Labels: map[string]string{}, Dependencies: []*DependentComponent{{ID: "web"}}},
+			},
+		},
+		{
+			caption: "remove an undefined component",
+			overlay: &OverlayDefinition{
+				Remove: []string{"cdn"},
+			},
+			err: true,
+		},
+		{
+			caption: "add a component already defined",
+			overlay: &OverlayDefinition{
+				Add: []*Component{{ID: "db"}},
+			},
+			err: true,
+		},
+		{
+			caption: "patch an undefined component",
+			overlay: &OverlayDefinition{
+				Patch: []*Patch{{ID: "cdn"}},
+			},
+			err: true,
+		},
+		{
+			caption: "remove a dependency that does not exist",
+			overlay: &OverlayDefinition{
+				Patch: []*Patch{{ID: "db", RemoveDependencies: []string{"web"}}},
+			},
+			err: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			cs, err := ApplyOverlay(base, tt.overlay)
+			if tt.err {
+				if err == nil {
+					t.Fatal("an error must occur")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cs, tt.expected) {
+				t.Errorf("unexpected components; want: %+v, got: %+v", tt.expected, cs)
+			}
+			if base[0].Labels["debug"] != "true" || len(base[0].Dependencies) != 2 {
+				t.Errorf("base components must not be modified")
+			}
+		})
+	}
+}
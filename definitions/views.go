@@ -0,0 +1,107 @@
+package definitions
+
+import (
+	"io"
+	"strings"
+
+	"github.com/nihei9/felipe/query"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	DefinitionKindViews = "views"
+)
+
+func ReadViewsDefinition(r io.Reader) (*ViewsDefinition, error) {
+	def := &ViewsDefinition{}
+	err := yaml.NewDecoder(r).Decode(def)
+	if err != nil {
+		return nil, err
+	}
+
+	err = def.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return def, nil
+}
+
+type ViewsDefinition struct {
+	Version string  `yaml:"version"`
+	Kind    string  `yaml:"kind"`
+	Views   []*View `yaml:"views"`
+}
+
+func (def *ViewsDefinition) validate() error {
+	if def.Version == "" {
+		return errorVersionIsMissing
+	}
+	if def.Kind == "" {
+		return errorKindIsMissing
+	}
+	if def.Kind != DefinitionKindViews {
+		return errorKindIsNotViews
+	}
+	if len(def.Views) <= 0 {
+		return errorViewsHasNoView
+	}
+	known := map[string]bool{}
+	for _, v := range def.Views {
+		if v == nil {
+			return errorViewsHasEmptyView
+		}
+
+		err := v.validate()
+		if err != nil {
+			return err
+		}
+
+		if known[v.Name] {
+			return errorViewNameIsDuplicated
+		}
+		known[v.Name] = true
+	}
+
+	return nil
+}
+
+// View is a saved combination of a query, faces and an output format. `faces` is a path relative to
+// the views definition, and `group_by` is a label key whose values group components into clusters,
+// which only the `dot` format draws. The format defaults to `svg`.
+type View struct {
+	Name            string `yaml:"name"`
+	Filter          string `yaml:"filter"`
+	Complementation string `yaml:"complementation"`
+	GroupBy         string `yaml:"group_by"`
+	Faces           string `yaml:"faces"`
+	Format          string `yaml:"format"`
+}
+
+func (v *View) validate() error {
+	if v.Name == "" {
+		return errorViewNameIsMissing
+	}
+	// A name is used as a file name of the output.
+	if strings.ContainsAny(v.Name, `/\`) || v.Name == "." || v.Name == ".." {
+		return errorViewNameIsInvalid
+	}
+	// Only DOT can draw clusters.
+	if v.GroupBy != "" && v.Format != "dot" {
+		return errorViewGroupByRequiresDOT
+	}
+	if v.Filter != "" {
+		_, err := query.ParseFilter(v.Filter)
+		if err != nil {
+			return err
+		}
+	}
+	if v.Complementation != "" {
+		_, err := query.ParseComplementation(v.Complementation, nil, query.Traversal{})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package definitions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestViewsDefinition(t *testing.T) {
+	tests := []struct {
+		caption string
+		data    string
+		err     error
+	}{
+		{
+			caption: "`views` has some views",
+			data: `
+version: 1
+kind: views
+views:
+- name: payments-context
+  filter: context=payments
+  complementation: dep=1,rdep=1
+  group_by: team
+  faces: faces.yaml
+  format: dot
+- name: all
+  format: svg
+`,
+		},
+		{
+			caption: "`views[].group_by` is specified with a format other than `dot`",
+			data: `
+version: 1
+kind: views
+views:
+- name: all
+  group_by: team
+  format: svg
+`,
+			err: errorViewGroupByRequiresDOT,
+		},
+		{
+			caption: "`views[].group_by` is specified without a format",
+			data: `
+version: 1
+kind: views
+views:
+- name: all
+  group_by: team
+`,
+			err: errorViewGroupByRequiresDOT,
+		},
+		{
+			caption: "`kind` is not `views`",
+			data: `
+version: 1
+kind: foo
+views:
+- name: all
+`,
+			err: errorKindIsNotViews,
+		},
+		{
+			caption: "`views` has no view",
+			data: `
+version: 1
+kind: views
+views:
+`,
+			err: errorViewsHasNoView,
+		},
+		{
+			caption: "`views[]` includes an empty view",
+			data: `
+version: 1
+kind: views
+views:
+- name: all
+-
+`,
+			err: errorViewsHasEmptyView,
+		},
+		{
+			caption: "`views[].name` is not specified",
+			data: `
+version: 1
+kind: views
+views:
+- filter: context=payments
+`,
+			err: errorViewNameIsMissing,
+		},
+		{
+			caption: "`views[].name` contains a path separator",
+			data: `
+version: 1
+kind: views
+views:
+- name: payments/context
+`,
+			err: errorViewNameIsInvalid,
+		},
+		{
+			caption: "`views[].name` is duplicated",
+			data: `
+version: 1
+kind: views
+views:
+- name: all
+- name: all
+`,
+			err: errorViewNameIsDuplicated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			_, err := ReadViewsDefinition(strings.NewReader(tt.data))
+			if err != tt.err {
+				t.Error(err)
+			}
+		})
+	}
+
+	_, err := ReadViewsDefinition(strings.NewReader(`
+version: 1
+kind: views
+views:
+- name: all
+  complementation: dep=x
+`))
+	if err == nil {
+		t.Error("a malformed complementation must be an error")
+	}
+}
@@ -0,0 +1,112 @@
+package drift
+
+import (
+	"sort"
+
+	"github.com/nihei9/felipe/component"
+)
+
+// Aliases maps an alias of a component to its canonical ID.
+type Aliases map[component.ComponentID]component.ComponentID
+
+func (a Aliases) resolve(id component.ComponentID) component.ComponentID {
+	if canonical, ok := a[id]; ok {
+		return canonical
+	}
+	return id
+}
+
+type Dependency struct {
+	From component.ComponentID
+	To   component.ComponentID
+}
+
+type Report struct {
+	MissingInActual        []component.ComponentID
+	MissingInDeclared      []component.ComponentID
+	UndeclaredDependencies []*Dependency
+	UnusedDependencies     []*Dependency
+}
+
+func (r *Report) HasDrift() bool {
+	return len(r.MissingInActual) > 0 ||
+		len(r.MissingInDeclared) > 0 ||
+		len(r.UndeclaredDependencies) > 0 ||
+		len(r.UnusedDependencies) > 0
+}
+
+// Compare reports differences between declared and actual components.
+// Hidden components are regarded as templates and excluded from the comparison.
+func Compare(declared *component.Components, actual *component.Components, aliases Aliases) *Report {
+	dGraph := newGraph(declared, aliases)
+	aGraph := newGraph(actual, aliases)
+
+	return &Report{
+		MissingInActual:        dGraph.componentsNotIn(aGraph),
+		MissingInDeclared:      aGraph.componentsNotIn(dGraph),
+		UndeclaredDependencies: aGraph.dependenciesNotIn(dGraph),
+		UnusedDependencies:     dGraph.dependenciesNotIn(aGraph),
+	}
+}
+
+type graph struct {
+	components   map[component.ComponentID]bool
+	dependencies map[Dependency]bool
+}
+
+func newGraph(cs *component.Components, aliases Aliases) *graph {
+	g := &graph{
+		components:   map[component.ComponentID]bool{},
+		dependencies: map[Dependency]bool{},
+	}
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		if c.IsHidden() {
+			continue
+		}
+
+		from := aliases.resolve(c.ID)
+		g.components[from] = true
+		for depID := range c.Dependencies {
+			to := aliases.resolve(depID)
+			if to == from {
+				continue
+			}
+			g.dependencies[Dependency{From: from, To: to}] = true
+		}
+	}
+
+	return g
+}
+
+func (g *graph) componentsNotIn(other *graph) []component.ComponentID {
+	ids := []component.ComponentID{}
+	for id := range g.components {
+		if !other.components[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+func (g *graph) dependenciesNotIn(other *graph) []*Dependency {
+	deps := []*Dependency{}
+	for dep := range g.dependencies {
+		if !other.dependencies[dep] {
+			d := dep
+			deps = append(deps, &d)
+		}
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].From != deps[j].From {
+			return deps[i].From < deps[j].From
+		}
+		return deps[i].To < deps[j].To
+	})
+
+	return deps
+}
@@ -0,0 +1,65 @@
+package drift
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+)
+
+func TestCompare(t *testing.T) {
+	declared := component.NewComponents()
+	actual := component.NewComponents()
+	for _, e := range []struct {
+		cs     *component.Components
+		id     component.ComponentID
+		deps   []component.ComponentID
+		hidden bool
+	}{
+		{cs: declared, id: "frontend", deps: []component.ComponentID{"payments", "users"}},
+		{cs: declared, id: "payments", deps: []component.ComponentID{"db"}},
+		{cs: declared, id: "users", deps: []component.ComponentID{"db"}},
+		{cs: declared, id: "db"},
+		{cs: declared, id: "template", deps: []component.ComponentID{"db"}, hidden: true},
+		{cs: actual, id: "web", deps: []component.ComponentID{"payments-svc", "db"}},
+		{cs: actual, id: "payments-svc", deps: []component.ComponentID{"db"}},
+		{cs: actual, id: "db"},
+		{cs: actual, id: "cache"},
+	} {
+		c := component.NewComponent(component.NilComponentID, e.id)
+		for _, d := range e.deps {
+			c.DependOn(d, &component.Relation{})
+		}
+		if e.hidden {
+			c.Hide()
+		}
+		e.cs.Add(c)
+	}
+	aliases := Aliases{
+		"web":          "frontend",
+		"payments-svc": "payments",
+	}
+
+	r := Compare(declared, actual, aliases)
+	if !r.HasDrift() {
+		t.Fatal("drift must be detected")
+	}
+	if want := []component.ComponentID{"users"}; !reflect.DeepEqual(r.MissingInActual, want) {
+		t.Errorf("unexpected components missing in actual; want: %v, got: %v", want, r.MissingInActual)
+	}
+	if want := []component.ComponentID{"cache"}; !reflect.DeepEqual(r.MissingInDeclared, want) {
+		t.Errorf("unexpected components missing in declared; want: %v, got: %v", want, r.MissingInDeclared)
+	}
+	if want := []*Dependency{{From: "frontend", To: "db"}}; !reflect.DeepEqual(r.UndeclaredDependencies, want) {
+		t.Errorf("unexpected undeclared dependencies; want: %v, got: %v", want, r.UndeclaredDependencies)
+	}
+	wantUnused := []*Dependency{{From: "frontend", To: "users"}, {From: "users", To: "db"}}
+	if !reflect.DeepEqual(r.UnusedDependencies, wantUnused) {
+		t.Errorf("unexpected unused dependencies; want: %v, got: %v", wantUnused, r.UnusedDependencies)
+	}
+
+	r = Compare(declared, declared, nil)
+	if r.HasDrift() {
+		t.Errorf("the same components must not drift; got: %+v", r)
+	}
+}
@@ -0,0 +1,116 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nihei9/felipe/component"
+)
+
+const (
+	CypherNodeLabel           = "Component"
+	CypherDefaultRelationType = "DEPENDS_ON"
+	cypherDescriptionProperty = "description"
+	cypherComponentIDProperty = "id"
+)
+
+// WriteCypher writes Cypher statements that merge components as nodes and
+// dependencies as relationships. Labels and relation attributes become
+// properties, and a relation description becomes the relationship type.
+// Dependencies on components that are not in cs are omitted.
+func WriteCypher(w io.Writer, cs *component.Components) error {
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		_, err := fmt.Fprintf(w, "MERGE (n:%s {%s: %s})%s;\n",
+			CypherNodeLabel, cypherComponentIDProperty, cypherString(c.ID.String()), cypherSetClause("n", c.Labels))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		for _, depID := range sortedDependencies(c) {
+			if _, ok := cs.Get(depID); !ok {
+				continue
+			}
+			rel := c.Dependencies[depID]
+			props := map[string]string{}
+			for k, v := range rel.Attributes {
+				props[k] = v
+			}
+			if rel.Description != "" {
+				props[cypherDescriptionProperty] = rel.Description
+			}
+
+			_, err := fmt.Fprintf(w, "MERGE (a:%s {%s: %s}) MERGE (b:%s {%s: %s}) MERGE (a)-[r:%s]->(b)%s;\n",
+				CypherNodeLabel, cypherComponentIDProperty, cypherString(c.ID.String()),
+				CypherNodeLabel, cypherComponentIDProperty, cypherString(depID.String()),
+				cypherRelationType(rel.Description), cypherSetClause("r", props))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func cypherSetClause(variable string, props map[string]string) string {
+	if len(props) <= 0 {
+		return ""
+	}
+
+	keys := []string{}
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	assignments := []string{}
+	for _, k := range keys {
+		assignments = append(assignments, fmt.Sprintf("%s.%s = %s", variable, cypherName(k), cypherString(props[k])))
+	}
+
+	return " SET " + strings.Join(assignments, ", ")
+}
+
+// cypherRelationType converts a relation description into an upper snake case
+// relationship type such as `READS_FROM`.
+func cypherRelationType(description string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.TrimSpace(description) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if underscore && b.Len() > 0 {
+				b.WriteRune('_')
+			}
+			underscore = false
+			b.WriteRune(unicode.ToUpper(r))
+			continue
+		}
+		underscore = true
+	}
+	if b.Len() <= 0 {
+		return CypherDefaultRelationType
+	}
+	relType := b.String()
+	if unicode.IsDigit(rune(relType[0])) {
+		return cypherName(relType)
+	}
+
+	return relType
+}
+
+func cypherName(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+func cypherString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `'`, `\'`, -1)
+	return "'" + s + "'"
+}
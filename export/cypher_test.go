@@ -0,0 +1,35 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+)
+
+func TestWriteCypher(t *testing.T) {
+	// c1 -> c2, c1 -> c3 (undefined)
+	cs := component.NewComponents()
+	c1 := component.NewComponent(component.NilComponentID, "c1")
+	c1.AddLabel("team", "payments")
+	c1.DependOn("c3", &component.Relation{Description: "reads"})
+	c1.DependOn("c2", &component.Relation{Description: "calls"})
+	cs.Add(c1)
+	c2 := component.NewComponent(component.NilComponentID, "c2")
+	c2.AddLabel("tier", "critical")
+	c2.AddLabel("owner", "it's")
+	cs.Add(c2)
+
+	b := &bytes.Buffer{}
+	err := WriteCypher(b, cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "MERGE (n:Component {id: 'c1'}) SET n.`team` = 'payments';\n" +
+		"MERGE (n:Component {id: 'c2'}) SET n.`owner` = 'it\\'s', n.`tier` = 'critical';\n" +
+		"MERGE (a:Component {id: 'c1'}) MERGE (b:Component {id: 'c2'}) MERGE (a)-[r:CALLS]->(b) SET r.`description` = 'calls';\n"
+	if b.String() != want {
+		t.Errorf("unexpected output; want:\n%s\ngot:\n%s", want, b.String())
+	}
+}
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+)
+
+const (
+	gexfNamespace    = "http://www.gexf.net/1.2draft"
+	gexfVizNamespace = "http://www.gexf.net/1.2draft/viz"
+	gexfVersion      = "1.2"
+
+	gexfAttributeRelation = "relation"
+)
+
+// gexfShapes maps DOT shapes to GEXF viz shapes.
+var gexfShapes = map[string]string{
+	"ellipse":   "disc",
+	"oval":      "disc",
+	"circle":    "disc",
+	"box":       "square",
+	"rect":      "square",
+	"rectangle": "square",
+	"square":    "square",
+	"triangle":  "triangle",
+	"diamond":   "diamond",
+}
+
+type gexfDocument struct {
+	XMLName  xml.Name   `xml:"gexf"`
+	XMLNS    string     `xml:"xmlns,attr"`
+	XMLNSViz string     `xml:"xmlns:viz,attr"`
+	Version  string     `xml:"version,attr"`
+	Graph    *gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string            `xml:"defaultedgetype,attr"`
+	Attributes      []*gexfAttributes `xml:"attributes"`
+	Nodes           []*gexfNode       `xml:"nodes>node"`
+	Edges           []*gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string           `xml:"class,attr"`
+	Attributes []*gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues *gexfAttValues `xml:"attvalues,omitempty"`
+	Color     *gexfColor     `xml:"viz:color,omitempty"`
+	Shape     *gexfShape     `xml:"viz:shape,omitempty"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Label     string         `xml:"label,attr,omitempty"`
+	AttValues *gexfAttValues `xml:"attvalues,omitempty"`
+}
+
+type gexfAttValues struct {
+	Values []*gexfAttValue `xml:"attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type gexfColor struct {
+	R uint8 `xml:"r,attr"`
+	G uint8 `xml:"g,attr"`
+	B uint8 `xml:"b,attr"`
+}
+
+type gexfShape struct {
+	Value string `xml:"value,attr"`
+}
+
+// WriteGEXF writes components in GEXF. Labels become node attributes and
+// relation descriptions become edge attributes. Colors and shapes resolved
+// from faces are written as viz attributes.
+func WriteGEXF(w io.Writer, cs *component.Components, fs []*face.Face) error {
+	keys := labelKeys(cs)
+	labelAttrIDs := map[string]string{}
+	nodeAttrs := &gexfAttributes{Class: "node"}
+	for i, k := range keys {
+		id := fmt.Sprintf("label%d", i)
+		labelAttrIDs[k] = id
+		nodeAttrs.Attributes = append(nodeAttrs.Attributes, &gexfAttribute{ID: id, Title: k, Type: "string"})
+	}
+	edgeAttrs := &gexfAttributes{
+		Class: "edge",
+		Attributes: []*gexfAttribute{
+			{ID: gexfAttributeRelation, Title: "relation", Type: "string"},
+		},
+	}
+
+	doc := &gexfDocument{
+		XMLNS:    gexfNamespace,
+		XMLNSViz: gexfVizNamespace,
+		Version:  gexfVersion,
+		Graph: &gexfGraph{
+			DefaultEdgeType: "directed",
+			Attributes:      []*gexfAttributes{nodeAttrs, edgeAttrs},
+		},
+	}
+
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		v, err := resolveVisual(c, cs, fs)
+		if err != nil {
+			return err
+		}
+
+		n := &gexfNode{
+			ID:    c.ID.String(),
+			Label: v.label,
+		}
+		values := []*gexfAttValue{}
+		for _, k := range keys {
+			value, ok := c.Labels[k]
+			if !ok {
+				continue
+			}
+			values = append(values, &gexfAttValue{For: labelAttrIDs[k], Value: value})
+		}
+		if len(values) > 0 {
+			n.AttValues = &gexfAttValues{Values: values}
+		}
+		color := v.fillColor
+		if color == nil {
+			color = v.color
+		}
+		if color != nil {
+			n.Color = &gexfColor{R: color.R, G: color.G, B: color.B}
+		}
+		if shape, ok := gexfShapes[v.shape]; ok {
+			n.Shape = &gexfShape{Value: shape}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+
+		for _, depID := range sortedDependencies(c) {
+			if _, ok := cs.Get(depID); !ok {
+				continue
+			}
+			rel := c.Dependencies[depID]
+			doc.Graph.Edges = append(doc.Graph.Edges, &gexfEdge{
+				ID:     fmt.Sprintf("e%d", len(doc.Graph.Edges)),
+				Source: c.ID.String(),
+				Target: depID.String(),
+				Label:  rel.Description,
+				AttValues: &gexfAttValues{
+					Values: []*gexfAttValue{
+						{For: gexfAttributeRelation, Value: rel.Description},
+					},
+				},
+			})
+		}
+	}
+
+	return writeXML(w, doc)
+}
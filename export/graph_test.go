@@ -0,0 +1,87 @@
+package export
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/query"
+)
+
+func TestWriteGraphs(t *testing.T) {
+	// c1 -> c2, c1 -> c3 (undefined)
+	cs := component.NewComponents()
+	c1 := component.NewComponent(component.NilComponentID, "c1")
+	c1.AddLabel("team", "payments")
+	c1.DependOn("c3", &component.Relation{Description: "reads"})
+	c1.DependOn("c2", &component.Relation{Description: "calls"})
+	cs.Add(c1)
+	c2 := component.NewComponent(component.NilComponentID, "c2")
+	c2.AddLabel("tier", "critical")
+	cs.Add(c2)
+
+	fs := []*face.Face{
+		{
+			Filter: query.LabelsFilter{
+				Labels: map[string]string{"tier": "critical"},
+			},
+			Attributes: map[string]string{
+				"fillcolor": "red",
+				"shape":     "box",
+				"label":     `"{tier}"`,
+			},
+		},
+	}
+
+	tests := []struct {
+		caption string
+		write   func(io.Writer, *component.Components, []*face.Face) error
+		want    []string
+	}{
+		{
+			caption: "GraphML",
+			write:   WriteGraphML,
+			want: []string{
+				`<key id="label0" for="node" attr.name="team" attr.type="string"></key>`,
+				`<data key="label0">payments</data>`,
+				`<y:Fill color="#FF0000"></y:Fill>`,
+				`<y:NodeLabel>critical</y:NodeLabel>`,
+				`<y:Shape type="rectangle"></y:Shape>`,
+				`<edge source="c1" target="c2">`,
+				`<data key="relation">calls</data>`,
+			},
+		},
+		{
+			caption: "GEXF",
+			write:   WriteGEXF,
+			want: []string{
+				`<attribute id="label1" title="tier" type="string"></attribute>`,
+				`<node id="c2" label="critical">`,
+				`<viz:color r="255" g="0" b="0"></viz:color>`,
+				`<viz:shape value="square"></viz:shape>`,
+				`<edge id="e0" source="c1" target="c2" label="calls">`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			err := tt.write(b, cs, fs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := b.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output must contain %q; got:\n%s", want, out)
+				}
+			}
+			if strings.Contains(out, `target="c3"`) {
+				t.Errorf("edges to components out of the set must be omitted; got:\n%s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+)
+
+const (
+	graphMLNamespace  = "http://graphml.graphdrawing.org/xmlns"
+	graphMLYNamespace = "http://www.yworks.com/xml/graphml"
+
+	graphMLKeyNodeGraphics = "ng"
+	graphMLKeyRelation     = "relation"
+)
+
+// graphMLShapes maps DOT shapes to yEd shape node types.
+var graphMLShapes = map[string]string{
+	"box":           "rectangle",
+	"rect":          "rectangle",
+	"rectangle":     "rectangle",
+	"square":        "rectangle",
+	"ellipse":       "ellipse",
+	"oval":          "ellipse",
+	"circle":        "ellipse",
+	"diamond":       "diamond",
+	"triangle":      "triangle",
+	"hexagon":       "hexagon",
+	"octagon":       "octagon",
+	"parallelogram": "parallelogram",
+	"trapezium":     "trapezoid",
+}
+
+type graphMLDocument struct {
+	XMLName xml.Name      `xml:"graphml"`
+	XMLNS   string        `xml:"xmlns,attr"`
+	XMLNSY  string        `xml:"xmlns:y,attr"`
+	Keys    []*graphMLKey `xml:"key"`
+	Graph   *graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID         string `xml:"id,attr"`
+	For        string `xml:"for,attr"`
+	Name       string `xml:"attr.name,attr,omitempty"`
+	Type       string `xml:"attr.type,attr,omitempty"`
+	YFilesType string `xml:"yfiles.type,attr,omitempty"`
+}
+
+type graphMLGraph struct {
+	ID          string         `xml:"id,attr"`
+	EdgeDefault string         `xml:"edgedefault,attr"`
+	Nodes       []*graphMLNode `xml:"node"`
+	Edges       []*graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string         `xml:"id,attr"`
+	Data []*graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string         `xml:"source,attr"`
+	Target string         `xml:"target,attr"`
+	Data   []*graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key       string            `xml:"key,attr"`
+	Value     string            `xml:",chardata"`
+	ShapeNode *graphMLShapeNode `xml:"y:ShapeNode,omitempty"`
+}
+
+type graphMLShapeNode struct {
+	Fill        *graphMLColor     `xml:"y:Fill,omitempty"`
+	BorderStyle *graphMLColor     `xml:"y:BorderStyle,omitempty"`
+	NodeLabel   *graphMLNodeLabel `xml:"y:NodeLabel"`
+	Shape       *graphMLShape     `xml:"y:Shape,omitempty"`
+}
+
+type graphMLColor struct {
+	Color string `xml:"color,attr"`
+}
+
+type graphMLNodeLabel struct {
+	TextColor string `xml:"textColor,attr,omitempty"`
+	Text      string `xml:",chardata"`
+}
+
+type graphMLShape struct {
+	Type string `xml:"type,attr"`
+}
+
+// WriteGraphML writes components in GraphML. Labels become node attributes and
+// relation descriptions become edge attributes. Visual attributes resolved from
+// faces are written as yEd graphics.
+func WriteGraphML(w io.Writer, cs *component.Components, fs []*face.Face) error {
+	keys := labelKeys(cs)
+	labelKeyIDs := map[string]string{}
+	doc := &graphMLDocument{
+		XMLNS:  graphMLNamespace,
+		XMLNSY: graphMLYNamespace,
+		Keys: []*graphMLKey{
+			{ID: graphMLKeyNodeGraphics, For: "node", YFilesType: "nodegraphics"},
+			{ID: graphMLKeyRelation, For: "edge", Name: "relation", Type: "string"},
+		},
+		Graph: &graphMLGraph{
+			ID:          "G",
+			EdgeDefault: "directed",
+		},
+	}
+	for i, k := range keys {
+		id := fmt.Sprintf("label%d", i)
+		labelKeyIDs[k] = id
+		doc.Keys = append(doc.Keys, &graphMLKey{ID: id, For: "node", Name: k, Type: "string"})
+	}
+
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		v, err := resolveVisual(c, cs, fs)
+		if err != nil {
+			return err
+		}
+
+		n := &graphMLNode{
+			ID: c.ID.String(),
+			Data: []*graphMLData{
+				{Key: graphMLKeyNodeGraphics, ShapeNode: v.graphMLShapeNode()},
+			},
+		}
+		for _, k := range keys {
+			value, ok := c.Labels[k]
+			if !ok {
+				continue
+			}
+			n.Data = append(n.Data, &graphMLData{Key: labelKeyIDs[k], Value: value})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+
+		for _, depID := range sortedDependencies(c) {
+			if _, ok := cs.Get(depID); !ok {
+				continue
+			}
+			doc.Graph.Edges = append(doc.Graph.Edges, &graphMLEdge{
+				Source: c.ID.String(),
+				Target: depID.String(),
+				Data: []*graphMLData{
+					{Key: graphMLKeyRelation, Value: c.Dependencies[depID].Description},
+				},
+			})
+		}
+	}
+
+	return writeXML(w, doc)
+}
+
+func (v *visual) graphMLShapeNode() *graphMLShapeNode {
+	n := &graphMLShapeNode{
+		NodeLabel: &graphMLNodeLabel{
+			Text: v.label,
+		},
+	}
+	if v.fillColor != nil {
+		n.Fill = &graphMLColor{Color: v.fillColor.Hex()}
+	}
+	if v.color != nil {
+		n.BorderStyle = &graphMLColor{Color: v.color.Hex()}
+	}
+	if v.fontColor != nil {
+		n.NodeLabel.TextColor = v.fontColor.Hex()
+	}
+	if shape, ok := graphMLShapes[v.shape]; ok {
+		n.Shape = &graphMLShape{Type: shape}
+	}
+
+	return n
+}
+
+func writeXML(w io.Writer, doc interface{}) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+
+	return err
+}
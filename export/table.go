@@ -0,0 +1,93 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+
+	"github.com/nihei9/felipe/component"
+)
+
+const (
+	CommaCSV = ','
+	CommaTSV = '\t'
+)
+
+// WriteNodeTable writes a table that has a row per component. The first column
+// is the component ID and the rest are the labels sorted by their keys.
+func WriteNodeTable(w io.Writer, cs *component.Components, comma rune) error {
+	keys := labelKeys(cs)
+
+	tw := csv.NewWriter(w)
+	tw.Comma = comma
+	err := tw.Write(append([]string{"id"}, keys...))
+	if err != nil {
+		return err
+	}
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		row := []string{c.ID.String()}
+		for _, k := range keys {
+			row = append(row, c.Labels[k])
+		}
+		err := tw.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+	tw.Flush()
+
+	return tw.Error()
+}
+
+// WriteEdgeTable writes a table that has a row per dependency.
+func WriteEdgeTable(w io.Writer, cs *component.Components, comma rune) error {
+	tw := csv.NewWriter(w)
+	tw.Comma = comma
+	err := tw.Write([]string{"source", "target", "relation"})
+	if err != nil {
+		return err
+	}
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		for _, depID := range sortedDependencies(c) {
+			err := tw.Write([]string{c.ID.String(), depID.String(), c.Dependencies[depID].Description})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	tw.Flush()
+
+	return tw.Error()
+}
+
+func labelKeys(cs *component.Components) []string {
+	known := map[string]bool{}
+	keys := []string{}
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		for k := range c.Labels {
+			if known[k] {
+				continue
+			}
+			known[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedDependencies(c *component.Component) []component.ComponentID {
+	ids := []component.ComponentID{}
+	for id := range c.Dependencies {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
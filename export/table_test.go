@@ -0,0 +1,54 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+)
+
+func TestWriteTables(t *testing.T) {
+	// c1 -> c2, c1 -> c3 (undefined)
+	cs := component.NewComponents()
+	c1 := component.NewComponent(component.NilComponentID, "c1")
+	c1.AddLabel("team", "payments")
+	c1.DependOn("c3", &component.Relation{Description: "reads"})
+	c1.DependOn("c2", &component.Relation{Description: "calls"})
+	cs.Add(c1)
+	c2 := component.NewComponent(component.NilComponentID, "c2")
+	c2.AddLabel("tier", "critical")
+	cs.Add(c2)
+
+	tests := []struct {
+		caption string
+		write   func(*bytes.Buffer, *component.Components) error
+		want    string
+	}{
+		{
+			caption: "nodes as CSV",
+			write: func(b *bytes.Buffer, cs *component.Components) error {
+				return WriteNodeTable(b, cs, CommaCSV)
+			},
+			want: "id,team,tier\nc1,payments,\nc2,,critical\n",
+		},
+		{
+			caption: "edges as TSV",
+			write: func(b *bytes.Buffer, cs *component.Components) error {
+				return WriteEdgeTable(b, cs, CommaTSV)
+			},
+			want: "source\ttarget\trelation\nc1\tc2\tcalls\nc1\tc3\treads\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			err := tt.write(b, cs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("unexpected output; want: %q, got: %q", tt.want, b.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/internal/dotattr"
+)
+
+// visual holds the DOT attributes of a component that other formats can express.
+type visual struct {
+	label     string
+	fillColor *dotattr.RGB
+	color     *dotattr.RGB
+	fontColor *dotattr.RGB
+	shape     string
+}
+
+func resolveVisual(c *component.Component, cs *component.Components, fs []*face.Face) (*visual, error) {
+	attrs, err := face.Engine{Faces: fs, Components: cs}.Resolve(c)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &visual{
+		label: c.ID.String(),
+		shape: dotattr.Unquote(attrs["shape"]),
+	}
+	if label, ok := attrs["label"]; ok {
+		v.label = dotattr.Unquote(label)
+	}
+	if fill, ok := dotattr.ParseColor(attrs["fillcolor"]); ok {
+		v.fillColor = &fill
+	}
+	if color, ok := dotattr.ParseColor(attrs["color"]); ok {
+		v.color = &color
+		if v.fillColor == nil && strings.Contains(attrs["style"], "filled") {
+			v.fillColor = &color
+		}
+	}
+	if fontColor, ok := dotattr.ParseColor(attrs["fontcolor"]); ok {
+		v.fontColor = &fontColor
+	}
+
+	return v, nil
+}
@@ -0,0 +1,6 @@
+// Package face resolves visual attributes of components.
+//
+// A Face applies attributes to the components its filter selects. An Engine applies faces in order of
+// their priorities and expands templates in attribute values, such as `{{.ID}}` or `{{.Labels.team}}`.
+// Explain reports which faces contributed to each attribute, which helps to debug faces.
+package face
@@ -0,0 +1,174 @@
+package face
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/query"
+)
+
+type Face struct {
+	Name string
+
+	// Filter selects components the face applies to. A nil filter selects all components.
+	Filter query.Filter
+
+	Attributes map[string]string
+
+	// Priority orders faces. A face with a higher priority is applied later and overrides
+	// attributes of faces with a lower priority. Faces with the same priority are applied in order.
+	Priority int
+
+	// Default faces are applied before all other faces regardless of their priority.
+	Default bool
+
+	// Append holds attribute keys whose values are appended to the values set by preceding
+	// faces with `,` instead of overwriting them (e.g. `style`).
+	Append map[string]bool
+}
+
+// Graph holds attributes that apply to a whole graph rather than to each component.
+type Graph struct {
+	Attributes map[string]string
+
+	// Node and Edge are default attributes of all nodes and edges. Faces override them.
+	Node map[string]string
+	Edge map[string]string
+
+	// Legend is a label of a legend describing faces. No legend is generated when it is empty.
+	Legend string
+
+	// GroupBy is a label key. Components having the label are grouped into a cluster per value.
+	// Only the DOT renderer draws clusters; the others ignore it.
+	GroupBy string
+}
+
+// AttributeOrigin describes how an attribute value was resolved.
+type AttributeOrigin struct {
+	Value string
+
+	// Faces are names of the faces that contributed to the value in the applied order.
+	Faces []string
+}
+
+type Explanation struct {
+	// Matched are faces that matched the component in the applied order.
+	Matched    []*Face
+	Attributes map[string]*AttributeOrigin
+}
+
+// Engine resolves attributes of components from faces.
+type Engine struct {
+	Faces []*Face
+
+	// Components are referred to by templates that need other components, such as `.Dependents`.
+	// When it is nil, such values are zero.
+	Components *component.Components
+}
+
+func Resolve(c *component.Component, fs []*Face) (map[string]string, error) {
+	return Engine{Faces: fs}.Resolve(c)
+}
+
+func Explain(c *component.Component, fs []*Face) (*Explanation, error) {
+	return Engine{Faces: fs}.Explain(c)
+}
+
+func (eng Engine) Resolve(c *component.Component) (map[string]string, error) {
+	e, err := eng.Explain(c)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := map[string]string{}
+	for k, o := range e.Attributes {
+		attrs[k] = o.Value
+	}
+
+	return attrs, nil
+}
+
+func (eng Engine) Explain(c *component.Component) (*Explanation, error) {
+	e := &Explanation{
+		Matched:    []*Face{},
+		Attributes: map[string]*AttributeOrigin{},
+	}
+	for _, f := range sortFaces(eng.Faces) {
+		if f.Filter != nil {
+			pass, err := f.Filter.Pass(c)
+			if err != nil {
+				return nil, err
+			}
+			if !pass {
+				continue
+			}
+		}
+		e.Matched = append(e.Matched, f)
+
+		keys := []string{}
+		for k := range f.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := f.Attributes[k]
+			if templatedAttributes[k] {
+				expanded, err := expandTemplate(c, eng.Components, v)
+				if err != nil {
+					return nil, err
+				}
+				v = expanded
+			}
+
+			o, ok := e.Attributes[k]
+			if ok && f.Append[k] {
+				o.Value = appendValue(o.Value, v)
+				o.Faces = append(o.Faces, f.Name)
+				continue
+			}
+			e.Attributes[k] = &AttributeOrigin{
+				Value: v,
+				Faces: []string{f.Name},
+			}
+		}
+	}
+
+	return e, nil
+}
+
+func sortFaces(fs []*Face) []*Face {
+	sorted := append([]*Face{}, fs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Default != sorted[j].Default {
+			return sorted[i].Default
+		}
+		return sorted[i].Priority < sorted[j].Priority
+	})
+
+	return sorted
+}
+
+// appendValue joins attribute values with `,`. When either value is a quoted string,
+// the result is also quoted.
+func appendValue(base string, v string) string {
+	quoted := false
+	unquote := func(s string) string {
+		if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+			quoted = true
+			return s[1 : len(s)-1]
+		}
+		return s
+	}
+	base = unquote(base)
+	v = unquote(v)
+
+	joined := v
+	if base != "" {
+		joined = base + "," + v
+	}
+	if quoted {
+		return fmt.Sprintf("\"%s\"", joined)
+	}
+	return joined
+}
@@ -0,0 +1,163 @@
+package face
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/query"
+)
+
+func TestExplain(t *testing.T) {
+	c := component.NewComponent(component.NilComponentID, "c1")
+	c.AddLabel("tier", "critical")
+	c.AddLabel("name", "Payments")
+
+	critical := query.LabelsFilter{
+		Labels: map[string]string{"tier": "critical"},
+	}
+	fs := []*Face{
+		{
+			Name:     "highlight",
+			Filter:   critical,
+			Priority: 10,
+			Attributes: map[string]string{
+				"fillcolor": "red",
+				"style":     "filled",
+			},
+			Append: map[string]bool{"style": true},
+		},
+		{
+			Name:   "critical",
+			Filter: critical,
+			Attributes: map[string]string{
+				"fillcolor": "orange",
+				"label":     `"{name}"`,
+			},
+		},
+		{
+			Name: "unmatched",
+			Filter: query.LabelsFilter{
+				Labels: map[string]string{"tier": "low"},
+			},
+			Attributes: map[string]string{
+				"shape": "ellipse",
+			},
+		},
+		{
+			Name:    "default",
+			Default: true,
+			Attributes: map[string]string{
+				"shape": "box",
+				"style": `"rounded"`,
+			},
+		},
+	}
+
+	e, err := Explain(c, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matched := []string{}
+	for _, f := range e.Matched {
+		matched = append(matched, f.Name)
+	}
+	if want := []string{"default", "critical", "highlight"}; !reflect.DeepEqual(matched, want) {
+		t.Errorf("unexpected matched faces; want: %v, got: %v", want, matched)
+	}
+
+	want := map[string]*AttributeOrigin{
+		"shape":     {Value: "box", Faces: []string{"default"}},
+		"style":     {Value: `"rounded,filled"`, Faces: []string{"default", "highlight"}},
+		"fillcolor": {Value: "red", Faces: []string{"highlight"}},
+		"label":     {Value: `"Payments"`, Faces: []string{"critical"}},
+	}
+	if !reflect.DeepEqual(e.Attributes, want) {
+		for k, o := range e.Attributes {
+			t.Logf("%v: %+v", k, o)
+		}
+		t.Error("unexpected attributes")
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	cs := component.NewComponents()
+	c1 := component.NewComponent(component.NilComponentID, "c1")
+	c1.AddLabel("name", "Payments")
+	c1.AddLabel("tier", "critical")
+	c1.DependOn("c2", &component.Relation{})
+	cs.Add(c1)
+	c2 := component.NewComponent(component.NilComponentID, "c2")
+	c2.AddLabel("name", "<DB>")
+	cs.Add(c2)
+
+	tests := []struct {
+		caption   string
+		component *component.Component
+		template  string
+		value     string
+		err       bool
+	}{
+		{
+			caption:   "a simple template embeds labels",
+			component: c1,
+			template:  `"{name} ({tier})"`,
+			value:     `"Payments (critical)"`,
+		},
+		{
+			caption:   "a simple template falls back to a default value",
+			component: c2,
+			template:  `"{name}/{tier|unknown}"`,
+			value:     `"<DB>/unknown"`,
+		},
+		{
+			caption:   "a simple template cannot embed an undefined label",
+			component: c2,
+			template:  `"{tier}"`,
+			err:       true,
+		},
+		{
+			caption:   "a Go template refers to the ID, labels and relation counts",
+			component: c2,
+			template:  `{{quote (printf "%s %s in:%d out:%d" (upper .ID) (.Labels.tier | default "n/a") .Dependents .Dependencies)}}`,
+			value:     `"C2 n/a in:1 out:0"`,
+		},
+		{
+			caption:   "a Go template supports conditionals",
+			component: c1,
+			template:  `{{if eq .Labels.tier "critical"}}"!{{lower .Labels.name}}"{{else}}"{{.ID}}"{{end}}`,
+			value:     `"!payments"`,
+		},
+		{
+			caption:   "a Go template generates an HTML-like table",
+			component: c2,
+			template:  `<{{table . "name" "owner"}}>`,
+			value:     `<<TABLE BORDER="0" CELLBORDER="1" CELLSPACING="0"><TR><TD COLSPAN="2"><B>c2</B></TD></TR><TR><TD>name</TD><TD>&lt;DB&gt;</TD></TR></TABLE>>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			attrs, err := Engine{
+				Faces: []*Face{
+					{
+						Attributes: map[string]string{"label": tt.template},
+					},
+				},
+				Components: cs,
+			}.Resolve(tt.component)
+			if tt.err {
+				if err == nil {
+					t.Fatal("an error must occur")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if attrs["label"] != tt.value {
+				t.Errorf("unexpected value; want: %v, got: %v", tt.value, attrs["label"])
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package face
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/nihei9/felipe/component"
+)
+
+// templatedAttributes are attributes whose values are expanded as templates.
+var templatedAttributes = map[string]bool{
+	"label":   true,
+	"xlabel":  true,
+	"tooltip": true,
+}
+
+// templateData is the data that templates refer to with `.`.
+type templateData struct {
+	ID           string
+	Labels       map[string]string
+	Dependencies int
+	Dependents   int
+}
+
+var templateFuncs = template.FuncMap{
+	"default": func(def string, v string) string {
+		if v == "" {
+			return def
+		}
+		return v
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"quote": func(s string) string {
+		return fmt.Sprintf("\"%s\"", strings.Replace(s, "\"", "\\\"", -1))
+	},
+	"escape": html.EscapeString,
+	"table":  labelTable,
+}
+
+// expandTemplate expands a template of an attribute value. A template containing `{{` is
+// a Go template (text/template) and the others are simple templates that embed labels as
+// `{key}` or `{key|default}`.
+func expandTemplate(c *component.Component, cs *component.Components, tmpl string) (string, error) {
+	if !strings.Contains(tmpl, "{{") {
+		return constructLabel(c, tmpl)
+	}
+
+	t, err := template.New("").Funcs(templateFuncs).Option("missingkey=zero").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	err = t.Execute(&b, newTemplateData(c, cs))
+	if err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+func newTemplateData(c *component.Component, cs *component.Components) *templateData {
+	data := &templateData{
+		ID:           c.ID.String(),
+		Labels:       c.Labels,
+		Dependencies: len(c.Dependencies),
+	}
+	if cs != nil {
+		for _, id := range cs.GetIDs() {
+			d, _ := cs.Get(id)
+			if _, ok := d.Dependencies[c.ID]; ok {
+				data.Dependents++
+			}
+		}
+	}
+
+	return data
+}
+
+// labelTable generates an HTML-like Graphviz table listing labels of a component.
+// When no keys are given, all labels are listed in the order of their keys.
+func labelTable(data *templateData, keys ...string) string {
+	if len(keys) <= 0 {
+		for k := range data.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+
+	var b strings.Builder
+	b.WriteString(`<TABLE BORDER="0" CELLBORDER="1" CELLSPACING="0">`)
+	fmt.Fprintf(&b, `<TR><TD COLSPAN="2"><B>%s</B></TD></TR>`, html.EscapeString(data.ID))
+	for _, k := range keys {
+		v, ok := data.Labels[k]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "<TR><TD>%s</TD><TD>%s</TD></TR>", html.EscapeString(k), html.EscapeString(v))
+	}
+	b.WriteString("</TABLE>")
+
+	return b.String()
+}
+
+// constructLabel expands `{key}` to the value of the label and `{key|default}` to the value
+// of the label or the default value when the component does not have the label.
+func constructLabel(c *component.Component, tmpl string) (string, error) {
+	placeholders := []string{}
+	capture := false
+	var start int
+	var end int
+	for i, char := range tmpl {
+		switch char {
+		case '{':
+			if capture {
+				return "", fmt.Errorf("an embeded label cannot be nested")
+			}
+			capture = true
+			start = i
+		case '}':
+			if !capture {
+				return "", fmt.Errorf("an embeded label is malformed")
+			}
+			capture = false
+			end = i
+
+			placeholder := tmpl[start : end+1]
+			placeholders = append(placeholders, placeholder)
+		}
+	}
+
+	embeddedValues := []string{}
+	for _, p := range placeholders {
+		f := strings.SplitN(p[1:len(p)-1], "|", 2)
+		labelK := strings.TrimSpace(f[0])
+		labelV, ok := c.Labels[labelK]
+		if !ok {
+			if len(f) < 2 {
+				return "", fmt.Errorf("ID cannot include the undefined label `%s`", labelK)
+			}
+			labelV = strings.TrimSpace(f[1])
+		}
+		embeddedValues = append(embeddedValues, labelV)
+	}
+
+	label := tmpl
+	for i, p := range placeholders {
+		label = strings.Replace(label, p, embeddedValues[i], 1)
+	}
+
+	return label, nil
+}
@@ -0,0 +1,181 @@
+package impact
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nihei9/felipe/component"
+)
+
+const (
+	defaultCriticality = 1.0
+)
+
+// Weights holds the criticality of components having a label. The first key is
+// a label key and the second key is a label value.
+type Weights map[string]map[string]float64
+
+func (ws Weights) criticality(c *component.Component) float64 {
+	crit := 0.0
+	matched := false
+	for k, v := range c.Labels {
+		w, ok := ws[k][v]
+		if !ok {
+			continue
+		}
+		if !matched || w > crit {
+			crit = w
+		}
+		matched = true
+	}
+	if !matched {
+		return defaultCriticality
+	}
+	return crit
+}
+
+type Impact struct {
+	ID component.ComponentID
+
+	// Distance is the number of dependencies between the component and the nearest failing component.
+	Distance int
+
+	// Path is a chain of dependents from the failing component to the component.
+	Path []component.ComponentID
+
+	// Criticality is the largest weight among the labels of the component.
+	Criticality float64
+}
+
+type Result struct {
+	// Impacts are sorted by the distance and then the ID.
+	Impacts []*Impact
+
+	// Score is the sum of the criticality of the affected components divided by their distance.
+	// The failing components themselves are not counted.
+	Score float64
+}
+
+func (r *Result) Components(all *component.Components) *component.Components {
+	cs := component.NewComponents()
+	for _, i := range r.Impacts {
+		c, _ := all.Get(i.ID)
+		cs.Add(c)
+	}
+
+	return cs
+}
+
+type Analyzer struct {
+	AllComponents *component.Components
+	Weights       Weights
+}
+
+// Analyze computes everything transitively affected when the components identified
+// by ids fail.
+func (a Analyzer) Analyze(ids []component.ComponentID) (*Result, error) {
+	dependents := map[component.ComponentID][]component.ComponentID{}
+	for _, id := range a.AllComponents.GetIDs() {
+		c, _ := a.AllComponents.Get(id)
+		// Hidden components, such as templates of `base`, are not deployed, so nothing fails with them.
+		if c.IsHidden() {
+			continue
+		}
+		for depID := range c.Dependencies {
+			dependents[depID] = append(dependents[depID], id)
+		}
+	}
+	for _, ds := range dependents {
+		sort.Slice(ds, func(i, j int) bool {
+			return ds[i] < ds[j]
+		})
+	}
+
+	impacts := map[component.ComponentID]*Impact{}
+	queue := []*Impact{}
+	for _, id := range ids {
+		c, ok := a.AllComponents.Get(id)
+		if !ok {
+			return nil, fmt.Errorf("the component `%s` is undefined", id)
+		}
+		if _, ok := impacts[id]; ok {
+			continue
+		}
+		i := &Impact{
+			ID:          id,
+			Distance:    0,
+			Path:        []component.ComponentID{id},
+			Criticality: a.Weights.criticality(c),
+		}
+		impacts[id] = i
+		queue = append(queue, i)
+	}
+
+	r := &Result{}
+	for len(queue) > 0 {
+		pivot := queue[0]
+		queue = queue[1:]
+		r.Impacts = append(r.Impacts, pivot)
+
+		for _, dID := range dependents[pivot.ID] {
+			if _, ok := impacts[dID]; ok {
+				continue
+			}
+			d, _ := a.AllComponents.Get(dID)
+			path := append(append([]component.ComponentID{}, pivot.Path...), dID)
+			i := &Impact{
+				ID:          dID,
+				Distance:    pivot.Distance + 1,
+				Path:        path,
+				Criticality: a.Weights.criticality(d),
+			}
+			impacts[dID] = i
+			queue = append(queue, i)
+			r.Score += i.Criticality / float64(i.Distance)
+		}
+	}
+	sort.SliceStable(r.Impacts, func(i, j int) bool {
+		if r.Impacts[i].Distance != r.Impacts[j].Distance {
+			return r.Impacts[i].Distance < r.Impacts[j].Distance
+		}
+		return r.Impacts[i].ID < r.Impacts[j].ID
+	})
+
+	return r, nil
+}
+
+// DistanceFilter passes components affected at the distance.
+type DistanceFilter struct {
+	Result    *Result
+	Distance  int
+	OrFarther bool
+}
+
+func (f DistanceFilter) Filter(target *component.Components) (*component.Components, error) {
+	result := component.NewComponents()
+	for _, id := range target.GetIDs() {
+		c, _ := target.Get(id)
+		pass, err := f.Pass(c)
+		if err != nil {
+			return nil, err
+		}
+		if pass {
+			result.Add(c)
+		}
+	}
+
+	return result, nil
+}
+
+func (f DistanceFilter) Pass(target *component.Component) (bool, error) {
+	for _, i := range f.Result.Impacts {
+		if i.ID != target.ID {
+			continue
+		}
+		if f.OrFarther {
+			return i.Distance >= f.Distance, nil
+		}
+		return i.Distance == f.Distance, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,77 @@
+package impact
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+)
+
+func TestAnalyze(t *testing.T) {
+	// web -> api -> db
+	// batch -> db
+	// admin -> web
+	// base -> db (hidden)
+	cs := component.NewComponents()
+	for _, e := range []struct {
+		id     component.ComponentID
+		tier   string
+		deps   []component.ComponentID
+		hidden bool
+	}{
+		{id: "web", tier: "critical", deps: []component.ComponentID{"api"}},
+		{id: "api", tier: "critical", deps: []component.ComponentID{"db"}},
+		{id: "batch", tier: "low", deps: []component.ComponentID{"db"}},
+		{id: "admin", deps: []component.ComponentID{"web"}},
+		{id: "db"},
+		{id: "base", tier: "critical", deps: []component.ComponentID{"db"}, hidden: true},
+	} {
+		c := component.NewComponent(component.NilComponentID, e.id)
+		if e.tier != "" {
+			c.AddLabel("tier", e.tier)
+		}
+		for _, d := range e.deps {
+			c.DependOn(d, &component.Relation{})
+		}
+		if e.hidden {
+			c.Hide()
+		}
+		cs.Add(c)
+	}
+
+	a := Analyzer{
+		AllComponents: cs,
+		Weights: Weights{
+			"tier": {
+				"critical": 10,
+				"low":      0.5,
+			},
+		},
+	}
+	r, err := a.Analyze([]component.ComponentID{"db"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*Impact{
+		{ID: "db", Distance: 0, Path: []component.ComponentID{"db"}, Criticality: 1},
+		{ID: "api", Distance: 1, Path: []component.ComponentID{"db", "api"}, Criticality: 10},
+		{ID: "batch", Distance: 1, Path: []component.ComponentID{"db", "batch"}, Criticality: 0.5},
+		{ID: "web", Distance: 2, Path: []component.ComponentID{"db", "api", "web"}, Criticality: 10},
+		{ID: "admin", Distance: 3, Path: []component.ComponentID{"db", "api", "web", "admin"}, Criticality: 1},
+	}
+	if !reflect.DeepEqual(r.Impacts, want) {
+		for _, i := range r.Impacts {
+			t.Logf("%+v", i)
+		}
+		t.Fatal("unexpected impacts")
+	}
+	if wantScore := 10 + 0.5 + 10.0/2 + 1.0/3; r.Score != wantScore {
+		t.Errorf("unexpected score; want: %v, got: %v", wantScore, r.Score)
+	}
+
+	_, err = a.Analyze([]component.ComponentID{"unknown"})
+	if err == nil {
+		t.Error("an undefined component must be rejected")
+	}
+}
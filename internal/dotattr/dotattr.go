@@ -0,0 +1,78 @@
+// Package dotattr interprets values of DOT attributes that faces define, so that the formats other
+// than DOT can draw the same colors, shapes and labels.
+package dotattr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RGB is a color of 8 bits per channel.
+type RGB struct {
+	R uint8
+	G uint8
+	B uint8
+}
+
+// Hex returns the color in the `#RRGGBB` notation.
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// namedColors contains frequently used colors of the Graphviz (X11) color scheme.
+var namedColors = map[string]RGB{
+	"black":       {0x00, 0x00, 0x00},
+	"white":       {0xFF, 0xFF, 0xFF},
+	"gray":        {0xC0, 0xC0, 0xC0},
+	"grey":        {0xC0, 0xC0, 0xC0},
+	"lightgray":   {0xD3, 0xD3, 0xD3},
+	"lightgrey":   {0xD3, 0xD3, 0xD3},
+	"darkgray":    {0xA9, 0xA9, 0xA9},
+	"darkgrey":    {0xA9, 0xA9, 0xA9},
+	"red":         {0xFF, 0x00, 0x00},
+	"darkred":     {0x8B, 0x00, 0x00},
+	"pink":        {0xFF, 0xC0, 0xCB},
+	"orange":      {0xFF, 0xA5, 0x00},
+	"yellow":      {0xFF, 0xFF, 0x00},
+	"lightyellow": {0xFF, 0xFF, 0xE0},
+	"gold":        {0xFF, 0xD7, 0x00},
+	"green":       {0x00, 0xFF, 0x00},
+	"darkgreen":   {0x00, 0x64, 0x00},
+	"lightgreen":  {0x90, 0xEE, 0x90},
+	"blue":        {0x00, 0x00, 0xFF},
+	"navy":        {0x00, 0x00, 0x80},
+	"lightblue":   {0xAD, 0xD8, 0xE6},
+	"skyblue":     {0x87, 0xCE, 0xEB},
+	"cyan":        {0x00, 0xFF, 0xFF},
+	"magenta":     {0xFF, 0x00, 0xFF},
+	"purple":      {0xA0, 0x20, 0xF0},
+	"violet":      {0xEE, 0x82, 0xEE},
+	"brown":       {0xA5, 0x2A, 0x2A},
+}
+
+// ParseColor parses a DOT color that is either a named color or `#RRGGBB`.
+func ParseColor(s string) (RGB, bool) {
+	s = strings.ToLower(Unquote(s))
+	if c, ok := namedColors[s]; ok {
+		return c, true
+	}
+	if len(s) != 7 || s[0] != '#' {
+		return RGB{}, false
+	}
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return RGB{}, false
+	}
+
+	return RGB{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, true
+}
+
+// Unquote removes double quotes that faces use to write DOT string literals.
+func Unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,478 @@
+package layout
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+type Direction int
+
+const (
+	TopToBottom Direction = iota
+	LeftToRight
+)
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+type Node struct {
+	ID     string
+	Width  float64
+	Height float64
+
+	// X and Y are the center of the node. Layout sets them.
+	X float64
+	Y float64
+}
+
+type Edge struct {
+	From string
+	To   string
+
+	// Points is a polyline from the border of the source node to the border of the target node.
+	// Layout sets it.
+	Points []Point
+}
+
+type Graph struct {
+	Nodes []*Node
+	Edges []*Edge
+
+	// Width and Height are the size of the whole graph including margins. Layout sets them.
+	Width  float64
+	Height float64
+}
+
+type Options struct {
+	Direction Direction
+
+	// RankSep is the distance between ranks and NodeSep is the distance between nodes in the same rank.
+	RankSep float64
+	NodeSep float64
+	Margin  float64
+}
+
+const orderingSweeps = 8
+
+// vertex is a node or a dummy node that a long edge passes through.
+type vertex struct {
+	node  *Node
+	rank  int
+	order int
+
+	// breadth is the size across ranks and depth is the size along ranks.
+	breadth float64
+	depth   float64
+	pos     float64
+
+	in  []*vertex
+	out []*vertex
+}
+
+// chain is an edge split into segments between adjacent ranks.
+type chain struct {
+	edge     *Edge
+	vertices []*vertex
+	reversed bool
+}
+
+// Layout lays out a graph in layers (Sugiyama-style). Cycles are broken by reversing edges, nodes are
+// assigned to ranks by their longest path from sources, and crossings are reduced by the barycenter heuristic.
+func Layout(g *Graph, opts Options) error {
+	vs := make([]*vertex, 0, len(g.Nodes))
+	index := map[string]*vertex{}
+	for _, n := range g.Nodes {
+		v := &vertex{
+			node: n,
+		}
+		if opts.Direction == LeftToRight {
+			v.breadth, v.depth = n.Height, n.Width
+		} else {
+			v.breadth, v.depth = n.Width, n.Height
+		}
+		vs = append(vs, v)
+		index[n.ID] = v
+	}
+
+	chains := []*chain{}
+	selfLoops := []*Edge{}
+	for _, e := range g.Edges {
+		from, ok := index[e.From]
+		if !ok {
+			return fmt.Errorf("an edge refers to an undefined node `%s`", e.From)
+		}
+		to, ok := index[e.To]
+		if !ok {
+			return fmt.Errorf("an edge refers to an undefined node `%s`", e.To)
+		}
+		if from == to {
+			selfLoops = append(selfLoops, e)
+			continue
+		}
+		chains = append(chains, &chain{
+			edge:     e,
+			vertices: []*vertex{from, to},
+		})
+	}
+
+	removeCycles(vs, chains)
+	assignRanks(vs, chains)
+	vs = splitLongEdges(vs, chains)
+	ranks := orderRanks(vs)
+	assignPositions(ranks, opts)
+	placeNodes(g, ranks, chains, selfLoops, opts)
+
+	return nil
+}
+
+// removeCycles reverses edges that go back to a node on the current DFS path.
+func removeCycles(vs []*vertex, chains []*chain) {
+	succ := map[*vertex][]*chain{}
+	for _, c := range chains {
+		succ[c.vertices[0]] = append(succ[c.vertices[0]], c)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[*vertex]int{}
+	var visit func(v *vertex)
+	visit = func(v *vertex) {
+		state[v] = visiting
+		for _, c := range succ[v] {
+			w := c.vertices[1]
+			switch state[w] {
+			case unvisited:
+				visit(w)
+			case visiting:
+				c.reversed = true
+			}
+		}
+		state[v] = visited
+	}
+	for _, v := range vs {
+		if state[v] == unvisited {
+			visit(v)
+		}
+	}
+
+	for _, c := range chains {
+		if c.reversed {
+			c.vertices[0], c.vertices[1] = c.vertices[1], c.vertices[0]
+		}
+	}
+}
+
+// assignRanks assigns each node the length of the longest path from sources.
+func assignRanks(vs []*vertex, chains []*chain) {
+	indegree := map[*vertex]int{}
+	succ := map[*vertex][]*vertex{}
+	for _, c := range chains {
+		from, to := c.vertices[0], c.vertices[1]
+		succ[from] = append(succ[from], to)
+		indegree[to]++
+	}
+
+	queue := []*vertex{}
+	for _, v := range vs {
+		if indegree[v] == 0 {
+			queue = append(queue, v)
+		}
+	}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, w := range succ[v] {
+			if v.rank+1 > w.rank {
+				w.rank = v.rank + 1
+			}
+			indegree[w]--
+			if indegree[w] == 0 {
+				queue = append(queue, w)
+			}
+		}
+	}
+}
+
+// splitLongEdges inserts dummy vertices so that every segment connects adjacent ranks.
+func splitLongEdges(vs []*vertex, chains []*chain) []*vertex {
+	for _, c := range chains {
+		from, to := c.vertices[0], c.vertices[1]
+		path := []*vertex{from}
+		for r := from.rank + 1; r < to.rank; r++ {
+			d := &vertex{
+				rank: r,
+			}
+			vs = append(vs, d)
+			path = append(path, d)
+		}
+		path = append(path, to)
+		for i := 0; i < len(path)-1; i++ {
+			path[i].out = append(path[i].out, path[i+1])
+			path[i+1].in = append(path[i+1].in, path[i])
+		}
+		c.vertices = path
+	}
+
+	return vs
+}
+
+// orderRanks orders vertices in each rank to reduce crossings and returns the ranks.
+func orderRanks(vs []*vertex) [][]*vertex {
+	maxRank := 0
+	for _, v := range vs {
+		if v.rank > maxRank {
+			maxRank = v.rank
+		}
+	}
+	ranks := make([][]*vertex, maxRank+1)
+	for _, v := range vs {
+		v.order = len(ranks[v.rank])
+		ranks[v.rank] = append(ranks[v.rank], v)
+	}
+
+	best := snapshot(ranks)
+	bestCrossings := crossings(ranks)
+	for i := 0; i < orderingSweeps && bestCrossings > 0; i++ {
+		if i%2 == 0 {
+			for r := 1; r < len(ranks); r++ {
+				sortByBarycenter(ranks[r], func(v *vertex) []*vertex { return v.in })
+			}
+		} else {
+			for r := len(ranks) - 2; r >= 0; r-- {
+				sortByBarycenter(ranks[r], func(v *vertex) []*vertex { return v.out })
+			}
+		}
+		if c := crossings(ranks); c < bestCrossings {
+			best = snapshot(ranks)
+			bestCrossings = c
+		}
+	}
+	for r, rank := range best {
+		ranks[r] = rank
+		for i, v := range rank {
+			v.order = i
+		}
+	}
+
+	return ranks
+}
+
+func snapshot(ranks [][]*vertex) [][]*vertex {
+	s := make([][]*vertex, len(ranks))
+	for r, rank := range ranks {
+		s[r] = append([]*vertex{}, rank...)
+	}
+	return s
+}
+
+func sortByBarycenter(rank []*vertex, neighbors func(v *vertex) []*vertex) {
+	bary := map[*vertex]float64{}
+	for _, v := range rank {
+		ns := neighbors(v)
+		if len(ns) == 0 {
+			// A vertex without neighbors keeps its position.
+			bary[v] = float64(v.order)
+			continue
+		}
+		sum := 0.0
+		for _, n := range ns {
+			sum += float64(n.order)
+		}
+		bary[v] = sum / float64(len(ns))
+	}
+	sort.SliceStable(rank, func(i, j int) bool {
+		return bary[rank[i]] < bary[rank[j]]
+	})
+	for i, v := range rank {
+		v.order = i
+	}
+}
+
+func crossings(ranks [][]*vertex) int {
+	count := 0
+	for _, rank := range ranks {
+		type segment struct {
+			from int
+			to   int
+		}
+		segs := []segment{}
+		for _, v := range rank {
+			for _, w := range v.out {
+				segs = append(segs, segment{from: v.order, to: w.order})
+			}
+		}
+		for i := 0; i < len(segs); i++ {
+			for j := i + 1; j < len(segs); j++ {
+				a, b := segs[i], segs[j]
+				if (a.from < b.from && a.to > b.to) || (a.from > b.from && a.to < b.to) {
+					count++
+				}
+			}
+		}
+	}
+
+	return count
+}
+
+// assignPositions assigns positions across ranks. Vertices are moved toward the barycenters of their
+// neighbors while keeping their order and the distance between them.
+func assignPositions(ranks [][]*vertex, opts Options) {
+	for _, rank := range ranks {
+		pos := 0.0
+		for _, v := range rank {
+			v.pos = pos + v.breadth/2
+			pos += v.breadth + opts.NodeSep
+		}
+	}
+
+	for i := 0; i < orderingSweeps; i++ {
+		if i%2 == 0 {
+			for r := 1; r < len(ranks); r++ {
+				align(ranks[r], func(v *vertex) []*vertex { return v.in }, opts)
+			}
+		} else {
+			for r := len(ranks) - 2; r >= 0; r-- {
+				align(ranks[r], func(v *vertex) []*vertex { return v.out }, opts)
+			}
+		}
+	}
+
+	min := math.Inf(1)
+	for _, rank := range ranks {
+		for _, v := range rank {
+			min = math.Min(min, v.pos-v.breadth/2)
+		}
+	}
+	for _, rank := range ranks {
+		for _, v := range rank {
+			v.pos -= min
+		}
+	}
+}
+
+func align(rank []*vertex, neighbors func(v *vertex) []*vertex, opts Options) {
+	if len(rank) == 0 {
+		return
+	}
+
+	desired := make([]float64, len(rank))
+	for i, v := range rank {
+		ns := neighbors(v)
+		if len(ns) == 0 {
+			desired[i] = v.pos
+			continue
+		}
+		sum := 0.0
+		for _, n := range ns {
+			sum += n.pos
+		}
+		desired[i] = sum / float64(len(ns))
+	}
+
+	// Keep the order and the distance between vertices, then shift the whole rank so that
+	// it is centered on the desired positions on average.
+	pos := make([]float64, len(rank))
+	for i, v := range rank {
+		pos[i] = desired[i]
+		if i > 0 {
+			prev := rank[i-1]
+			min := pos[i-1] + prev.breadth/2 + opts.NodeSep + v.breadth/2
+			if pos[i] < min {
+				pos[i] = min
+			}
+		}
+	}
+	shift := 0.0
+	for i := range rank {
+		shift += desired[i] - pos[i]
+	}
+	shift /= float64(len(rank))
+	for i, v := range rank {
+		v.pos = pos[i] + shift
+	}
+}
+
+func placeNodes(g *Graph, ranks [][]*vertex, chains []*chain, selfLoops []*Edge, opts Options) {
+	rankCenters := make([]float64, len(ranks))
+	rankDepths := make([]float64, len(ranks))
+	offset := opts.Margin
+	for r, rank := range ranks {
+		for _, v := range rank {
+			rankDepths[r] = math.Max(rankDepths[r], v.depth)
+		}
+		rankCenters[r] = offset + rankDepths[r]/2
+		offset += rankDepths[r] + opts.RankSep
+	}
+
+	point := func(pos, depth float64) Point {
+		if opts.Direction == LeftToRight {
+			return Point{X: depth, Y: pos + opts.Margin}
+		}
+		return Point{X: pos + opts.Margin, Y: depth}
+	}
+
+	breadth := 0.0
+	for r, rank := range ranks {
+		for _, v := range rank {
+			breadth = math.Max(breadth, v.pos+v.breadth/2)
+			if v.node == nil {
+				continue
+			}
+			p := point(v.pos, rankCenters[r])
+			v.node.X, v.node.Y = p.X, p.Y
+		}
+	}
+
+	for _, c := range chains {
+		points := []Point{}
+		for i, v := range c.vertices {
+			depth := rankCenters[v.rank]
+			switch {
+			case i == 0:
+				depth += v.depth / 2
+			case i == len(c.vertices)-1:
+				depth -= v.depth / 2
+			}
+			points = append(points, point(v.pos, depth))
+		}
+		if c.reversed {
+			for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+				points[i], points[j] = points[j], points[i]
+			}
+		}
+		c.edge.Points = points
+	}
+
+	for _, e := range selfLoops {
+		var n *Node
+		for _, m := range g.Nodes {
+			if m.ID == e.From {
+				n = m
+				break
+			}
+		}
+		right := n.X + n.Width/2
+		e.Points = []Point{
+			{X: right, Y: n.Y - n.Height/4},
+			{X: right + opts.NodeSep/2, Y: n.Y - n.Height/4},
+			{X: right + opts.NodeSep/2, Y: n.Y + n.Height/4},
+			{X: right, Y: n.Y + n.Height/4},
+		}
+	}
+
+	depth := offset - opts.RankSep + opts.Margin
+	if len(ranks) == 0 || len(g.Nodes) == 0 {
+		depth = 2 * opts.Margin
+	}
+	size := point(breadth+opts.Margin, depth)
+	if len(selfLoops) > 0 {
+		size.X += opts.NodeSep / 2
+	}
+	g.Width, g.Height = size.X, size.Y
+}
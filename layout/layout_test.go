@@ -0,0 +1,113 @@
+package layout
+
+import (
+	"math"
+	"testing"
+)
+
+func newGraph(ids []string, edges [][2]string) *Graph {
+	g := &Graph{}
+	for _, id := range ids {
+		g.Nodes = append(g.Nodes, &Node{ID: id, Width: 40, Height: 20})
+	}
+	for _, e := range edges {
+		g.Edges = append(g.Edges, &Edge{From: e[0], To: e[1]})
+	}
+	return g
+}
+
+func nodes(g *Graph) map[string]*Node {
+	ns := map[string]*Node{}
+	for _, n := range g.Nodes {
+		ns[n.ID] = n
+	}
+	return ns
+}
+
+func TestLayout(t *testing.T) {
+	opts := Options{
+		RankSep: 30,
+		NodeSep: 10,
+		Margin:  5,
+	}
+
+	t.Run("nodes are placed in ranks by their longest path", func(t *testing.T) {
+		// a -> b -> c, a -> c, a -> d
+		g := newGraph([]string{"a", "b", "c", "d"}, [][2]string{{"a", "b"}, {"b", "c"}, {"a", "c"}, {"a", "d"}})
+		err := Layout(g, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ns := nodes(g)
+		wantY := map[string]float64{"a": 15, "b": 65, "c": 115, "d": 65}
+		for id, y := range wantY {
+			if ns[id].Y != y {
+				t.Errorf("unexpected Y of `%v`; want: %v, got: %v", id, y, ns[id].Y)
+			}
+		}
+		if math.Abs(ns["b"].X-ns["d"].X) < 50 {
+			t.Errorf("nodes in the same rank must not overlap; b: %v, d: %v", ns["b"].X, ns["d"].X)
+		}
+		if g.Height != 130 {
+			t.Errorf("unexpected height; want: 130, got: %v", g.Height)
+		}
+
+		for _, e := range g.Edges {
+			from, to := ns[e.From], ns[e.To]
+			first, last := e.Points[0], e.Points[len(e.Points)-1]
+			if first.Y != from.Y+from.Height/2 || last.Y != to.Y-to.Height/2 {
+				t.Errorf("an edge must connect borders of nodes; %v -> %v: %v", e.From, e.To, e.Points)
+			}
+		}
+		for _, e := range g.Edges {
+			if e.From == "a" && e.To == "c" && len(e.Points) != 3 {
+				t.Errorf("a long edge must pass through a dummy node; got: %v", e.Points)
+			}
+		}
+	})
+
+	t.Run("cycles are broken", func(t *testing.T) {
+		// a -> b -> c -> a
+		g := newGraph([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}})
+		err := Layout(g, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ns := nodes(g)
+		if !(ns["a"].Y < ns["b"].Y && ns["b"].Y < ns["c"].Y) {
+			t.Errorf("unexpected ranks; a: %v, b: %v, c: %v", ns["a"].Y, ns["b"].Y, ns["c"].Y)
+		}
+		e := g.Edges[2]
+		if first := e.Points[0]; first.Y != ns["c"].Y-ns["c"].Height/2 {
+			t.Errorf("a reversed edge must start from the source node; got: %v", e.Points)
+		}
+	})
+
+	t.Run("ranks go from left to right", func(t *testing.T) {
+		g := newGraph([]string{"a", "b"}, [][2]string{{"a", "b"}})
+		o := opts
+		o.Direction = LeftToRight
+		err := Layout(g, o)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ns := nodes(g)
+		if ns["a"].X != 25 || ns["b"].X != 95 || ns["a"].Y != ns["b"].Y {
+			t.Errorf("unexpected positions; a: %+v, b: %+v", ns["a"], ns["b"])
+		}
+		if g.Width != 120 || g.Height != 30 {
+			t.Errorf("unexpected size; want: 120x30, got: %vx%v", g.Width, g.Height)
+		}
+	})
+
+	t.Run("an edge to an undefined node", func(t *testing.T) {
+		g := newGraph([]string{"a"}, [][2]string{{"a", "b"}})
+		err := Layout(g, opts)
+		if err == nil {
+			t.Fatal("an error must occur")
+		}
+	})
+}
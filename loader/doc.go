@@ -0,0 +1,11 @@
+// Package loader loads components and faces from definition files.
+//
+// Components are read from definitions of `kind: components` and complemented with their bases, so
+// they are ready to be queried. Faces are read from a definition of `kind: faces` together with the
+// graph attributes it defines. The results are passed to the render package to generate diagrams,
+// or to the query package to select a part of the components.
+//
+// Overlays of `kind: overlay` remove, add and patch components of a base set of definitions before
+// they are complemented, so that one set of definitions describes variants such as environments.
+// LoadDir looks up overlays by name in the `overlays` directory under the directory of definitions.
+package loader
@@ -0,0 +1,30 @@
+package loader_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nihei9/felipe/loader"
+)
+
+func ExampleRead() {
+	cs, err := loader.Read(strings.NewReader(`kind: components
+version: 1
+components:
+  - id: web
+    dependencies:
+      - id: db
+  - id: db
+    labels:
+      tier: db
+`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	db, _ := cs.Get("db")
+	fmt.Println(db.Labels["tier"])
+	// Output:
+	// db
+}
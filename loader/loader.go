@@ -0,0 +1,190 @@
+package loader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/definitions"
+	"github.com/nihei9/felipe/face"
+)
+
+// Faces holds faces and graph attributes defined by a faces definition.
+type Faces struct {
+	Faces []*face.Face
+	Graph *face.Graph
+}
+
+// DefinitionFiles returns paths of definition files (`*.yaml`) directly under a directory. Files that
+// are the same as excludes, such as faces definitions kept in the directory, are left out.
+func DefinitionFiles(dir string, excludes ...string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, path := range paths {
+		if !sameAsAny(path, excludes) {
+			files = append(files, path)
+		}
+	}
+
+	return files, nil
+}
+
+func sameAsAny(path string, others []string) bool {
+	if len(others) == 0 {
+		return false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	for _, o := range others {
+		oInfo, err := os.Stat(o)
+		if err != nil {
+			continue
+		}
+		if os.SameFile(info, oInfo) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OverlayFile returns the path of an overlay, `<dir>/overlays/<name>.yaml`, for a directory of definitions.
+func OverlayFile(dir string, name string) string {
+	return filepath.Join(dir, "overlays", name+".yaml")
+}
+
+// LoadDir loads components from all definition files directly under a directory. Overlays named by
+// overlays are applied in order.
+func LoadDir(dir string, overlays ...string) (*component.Components, error) {
+	paths, err := DefinitionFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	overlayFiles := []string{}
+	for _, name := range overlays {
+		overlayFiles = append(overlayFiles, OverlayFile(dir, name))
+	}
+
+	return LoadFilesWithOverlays(paths, overlayFiles)
+}
+
+// LoadFiles loads components from definition files. Errors are prefixed with the path of the file.
+func LoadFiles(paths ...string) (*component.Components, error) {
+	return LoadFilesWithOverlays(paths, nil)
+}
+
+// LoadFilesWithOverlays loads components from definition files and applies overlay files in order
+// before complementing the components.
+func LoadFilesWithOverlays(paths []string, overlayFiles []string) (*component.Components, error) {
+	cDefs := []*definitions.Component{}
+	for _, path := range paths {
+		def, err := readComponentsDefinition(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		cDefs = append(cDefs, def.Components...)
+	}
+	for _, path := range overlayFiles {
+		o, err := readOverlayDefinition(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		cDefs, err = definitions.ApplyOverlay(cDefs, o)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+
+	return makeComponents(cDefs)
+}
+
+// Read loads components from definitions read from readers, such as stdin.
+func Read(rs ...io.Reader) (*component.Components, error) {
+	cDefs := []*definitions.Component{}
+	for _, r := range rs {
+		def, err := definitions.ReadComponentsDefinition(r)
+		if err != nil {
+			return nil, err
+		}
+
+		cDefs = append(cDefs, def.Components...)
+	}
+
+	return makeComponents(cDefs)
+}
+
+// LoadFaces loads faces from a faces definition file.
+func LoadFaces(path string) (*Faces, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fs, err := ReadFaces(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return fs, nil
+}
+
+// ReadFaces loads faces from a faces definition read from a reader.
+func ReadFaces(r io.Reader) (*Faces, error) {
+	def, err := definitions.ReadFacesDefinition(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Faces{
+		Faces: definitions.MakeFaceEntities(def),
+		Graph: definitions.MakeGraphEntity(def),
+	}, nil
+}
+
+func readComponentsDefinition(path string) (*definitions.ComponentsDefinition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return definitions.ReadComponentsDefinition(f)
+}
+
+func readOverlayDefinition(path string) (*definitions.OverlayDefinition, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("the overlay does not exist")
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return definitions.ReadOverlayDefinition(f)
+}
+
+func makeComponents(cDefs []*definitions.Component) (*component.Components, error) {
+	cs := component.NewComponents()
+	for _, cDef := range cDefs {
+		c := definitions.MakeComponentEntity(cDef)
+		cs.Add(c)
+	}
+	err := cs.Complement()
+	if err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
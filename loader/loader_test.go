@@ -0,0 +1,151 @@
+package loader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+)
+
+func TestLoadDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "felipe-loader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.yaml": `kind: components
+version: 1
+components:
+  - id: a
+    dependencies:
+      - id: b
+  - id: b
+`,
+		"c.yaml": `kind: components
+version: 1
+components:
+  - id: c
+    dependencies:
+      - id: a
+`,
+		"notes.txt": "not a definition",
+	}
+	for name, content := range files {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cs, err := LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []component.ComponentID{"a", "b", "c"} {
+		if _, ok := cs.Get(id); !ok {
+			t.Errorf("the component `%v` must be loaded", id)
+		}
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("kind: components\nversion: 1\ncomponents:\n  - {}\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadDir(dir)
+	if err == nil || !strings.HasPrefix(err.Error(), filepath.Join(dir, "broken.yaml")+": ") {
+		t.Errorf("an error must be prefixed with the file path; got: %v", err)
+	}
+}
+
+func TestReadFaces(t *testing.T) {
+	fs, err := ReadFaces(strings.NewReader(`kind: faces
+version: 1
+faces:
+  - name: db
+    targets:
+      match_labels:
+        tier: db
+    attributes:
+      shape: cylinder
+legend: {}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs.Faces) != 1 || fs.Faces[0].Name != "db" {
+		t.Errorf("unexpected faces; got: %+v", fs.Faces)
+	}
+	if fs.Graph == nil || fs.Graph.Legend != "Legend" {
+		t.Errorf("unexpected graph; got: %+v", fs.Graph)
+	}
+}
+
+func TestLoadDir_overlays(t *testing.T) {
+	dir, err := ioutil.TempDir("", "felipe-loader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = os.Mkdir(filepath.Join(dir, "overlays"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"components.yaml": `kind: components
+version: 1
+components:
+  - id: web
+    dependencies:
+      - id: mock-payments
+  - id: mock-payments
+`,
+		"overlays/prod.yaml": `kind: overlay
+version: 1
+remove:
+  - mock-payments
+add:
+  - id: payments
+patch:
+  - id: web
+    labels:
+      replicas: "3"
+    dependencies:
+      - id: payments
+`,
+	}
+	for name, content := range files {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cs, err := LoadDir(dir, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cs.Get("mock-payments"); ok {
+		t.Errorf("a removed component must not be loaded")
+	}
+	web, ok := cs.Get("web")
+	if !ok {
+		t.Fatal("`web` must be loaded")
+	}
+	if web.Labels["replicas"] != "3" {
+		t.Errorf("a patched label must be set; got: %v", web.Labels)
+	}
+	if _, ok := web.Dependencies["payments"]; !ok || len(web.Dependencies) != 1 {
+		t.Errorf("unexpected dependencies; got: %v", web.Dependencies)
+	}
+
+	_, err = LoadDir(dir, "dev")
+	if err == nil {
+		t.Errorf("an undefined overlay must be an error")
+	}
+}
@@ -0,0 +1,138 @@
+package lsp
+
+import (
+	"strings"
+)
+
+type symbolKind int
+
+const (
+	// symbolDefinition is `components[].id`.
+	symbolDefinition symbolKind = iota
+	// symbolDependency is `components[].dependencies[].id`.
+	symbolDependency
+	// symbolBase is `components[].base`.
+	symbolBase
+)
+
+// symbol is an occurrence of a component ID in a document.
+type symbol struct {
+	kind  symbolKind
+	id    string
+	rng   Range
+	owner string
+}
+
+type blockKey struct {
+	indent int
+	key    string
+}
+
+// scan finds component IDs in a components definition. YAML parsers do not report positions of values,
+// so it scans lines and tracks parent keys by indentation. It tolerates incomplete documents being edited.
+// Ranges are in UTF-16 code units as the protocol defines.
+func scan(text string) []*symbol {
+	symbols := []*symbol{}
+	stack := []blockKey{}
+	owner := ""
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		content := strings.TrimLeft(line, " ")
+		if content == "" || strings.HasPrefix(content, "#") {
+			continue
+		}
+		col := len(line) - len(content)
+		if strings.HasPrefix(content, "- ") || content == "-" {
+			rest := strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")
+			col += len(content) - len(rest)
+			content = rest
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= col {
+			stack = stack[:len(stack)-1]
+		}
+
+		i := strings.Index(content, ":")
+		if i < 0 {
+			continue
+		}
+		key := strings.TrimSpace(content[:i])
+		rawValue := content[i+1:]
+		value := strings.TrimSpace(rawValue)
+		if value == "" {
+			stack = append(stack, blockKey{indent: col, key: key})
+			continue
+		}
+
+		parent := ""
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1].key
+		}
+		var kind symbolKind
+		switch {
+		case key == "id" && parent == "components":
+			kind = symbolDefinition
+		case key == "id" && parent == "dependencies":
+			kind = symbolDependency
+		case key == "base" && parent == "components":
+			kind = symbolBase
+		default:
+			continue
+		}
+
+		id, start := unquoteValue(rawValue)
+		start += col + i + 1
+		if kind == symbolDefinition {
+			owner = id
+		}
+		symbols = append(symbols, &symbol{
+			kind: kind,
+			id:   id,
+			rng: Range{
+				Start: Position{Line: n, Character: utf16Offset(line, start)},
+				End:   Position{Line: n, Character: utf16Offset(line, start+len(id))},
+			},
+			owner: owner,
+		})
+	}
+
+	return symbols
+}
+
+// unquoteValue returns a scalar value without quotes and a trailing comment, and its offset in raw.
+func unquoteValue(raw string) (string, int) {
+	offset := len(raw) - len(strings.TrimLeft(raw, " "))
+	v := raw[offset:]
+	if len(v) > 0 && (v[0] == '"' || v[0] == '\'') {
+		if end := strings.IndexByte(v[1:], v[0]); end >= 0 {
+			return v[1 : end+1], offset + 1
+		}
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = v[:i]
+	}
+
+	return strings.TrimSpace(v), offset
+}
+
+// kindAt returns the kind of the symbol whose value is being written at the position. It is used to
+// complete values that are empty or incomplete.
+func kindAt(text string, pos Position) (symbolKind, bool) {
+	lines := strings.Split(text, "\n")
+	if pos.Line >= len(lines) {
+		return 0, false
+	}
+	head := lines[:pos.Line+1]
+	cur := strings.TrimRight(head[len(head)-1], "\r")
+	cur = cur[:byteOffset(cur, pos.Character)]
+	// A placeholder makes an empty value a symbol.
+	head[len(head)-1] = cur + " x"
+
+	for _, s := range scan(strings.Join(head, "\n")) {
+		if s.rng.Start.Line == pos.Line {
+			return s.kind, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,268 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const baseDoc = `version: 1
+kind: components
+components:
+- id: service
+  labels:
+    team: core
+    tier: backend
+- id: db
+  base: service
+`
+
+const editedDoc = `version: 1
+kind: components
+components:
+- id: payments
+  base: service
+  labels:
+    tier: critical
+  dependencies:
+  - id: db
+    relation: reads
+  - id: "cache"
+  - id: 
+`
+
+func TestScan(t *testing.T) {
+	want := []*symbol{
+		{kind: symbolDefinition, id: "payments", owner: "payments", rng: Range{Start: Position{Line: 3, Character: 6}, End: Position{Line: 3, Character: 14}}},
+		{kind: symbolBase, id: "service", owner: "payments", rng: Range{Start: Position{Line: 4, Character: 8}, End: Position{Line: 4, Character: 15}}},
+		{kind: symbolDependency, id: "db", owner: "payments", rng: Range{Start: Position{Line: 8, Character: 8}, End: Position{Line: 8, Character: 10}}},
+		{kind: symbolDependency, id: "cache", owner: "payments", rng: Range{Start: Position{Line: 10, Character: 9}, End: Position{Line: 10, Character: 14}}},
+	}
+	got := scan(editedDoc)
+	if !reflect.DeepEqual(got, want) {
+		for _, s := range got {
+			t.Logf("%+v", s)
+		}
+		t.Fatal("unexpected symbols")
+	}
+
+	kind, ok := kindAt(editedDoc, Position{Line: 11, Character: 8})
+	if !ok || kind != symbolDependency {
+		t.Errorf("an empty dependency ID must be completed; got: %v, %v", kind, ok)
+	}
+	_, ok = kindAt(editedDoc, Position{Line: 9, Character: 14})
+	if ok {
+		t.Error("a relation must not be completed")
+	}
+}
+
+func TestScan_nonASCII(t *testing.T) {
+	// Characters are counted in UTF-16 code units. 🗄 is encoded as a surrogate pair.
+	doc := "components:\n- id: 決済 # payments\n  dependencies:\n  - id: \"🗄db\"\n  - id: "
+	want := []*symbol{
+		{kind: symbolDefinition, id: "決済", owner: "決済", rng: Range{Start: Position{Line: 1, Character: 6}, End: Position{Line: 1, Character: 8}}},
+		{kind: symbolDependency, id: "🗄db", owner: "決済", rng: Range{Start: Position{Line: 3, Character: 9}, End: Position{Line: 3, Character: 13}}},
+	}
+	got := scan(doc)
+	if !reflect.DeepEqual(got, want) {
+		for _, s := range got {
+			t.Logf("%+v", s)
+		}
+		t.Fatal("unexpected symbols")
+	}
+
+	idx := map[string][]*symbol{"doc": got}
+	if sym := symbolAt(idx, "doc", Position{Line: 3, Character: 12}); sym == nil || sym.id != "🗄db" {
+		t.Errorf("a symbol must be found by a position in UTF-16 code units; got: %+v", sym)
+	}
+	if sym := symbolAt(idx, "doc", Position{Line: 1, Character: 9}); sym != nil {
+		t.Errorf("a position after a symbol must not match it; got: %+v", sym)
+	}
+	line := `  - id: "🗄db"`
+	for _, c := range []int{0, 9, 11, 13} {
+		if got := utf16Offset(line, byteOffset(line, c)); got != c {
+			t.Errorf("an offset must be converted back; want: %v, got: %v", c, got)
+		}
+	}
+	kind, ok := kindAt(doc, Position{Line: 4, Character: 8})
+	if !ok || kind != symbolDependency {
+		t.Errorf("an empty dependency ID must be completed; got: %v, %v", kind, ok)
+	}
+}
+
+type testClient struct {
+	in  bytes.Buffer
+	id  int
+	ids map[int]string
+}
+
+func (c *testClient) send(method string, params interface{}, request bool) {
+	msg := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+	}
+	if request {
+		c.id++
+		msg["id"] = c.id
+		c.ids[c.id] = method
+	}
+	body, _ := json.Marshal(msg)
+	fmt.Fprintf(&c.in, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func TestServe(t *testing.T) {
+	dir, err := ioutil.TempDir("", "felipe-lsp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "base.yaml"), []byte(baseDoc), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseURI := pathToURI(filepath.Join(dir, "base.yaml"))
+	uri := pathToURI(filepath.Join(dir, "payments.yaml"))
+	doc := map[string]interface{}{"uri": uri}
+	at := func(line, char int) map[string]interface{} {
+		return map[string]interface{}{
+			"textDocument": doc,
+			"position":     map[string]int{"line": line, "character": char},
+		}
+	}
+
+	c := &testClient{ids: map[int]string{}}
+	c.send("initialize", map[string]string{"rootUri": pathToURI(dir)}, true)
+	c.send("initialized", map[string]string{}, false)
+	c.send("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]string{"uri": uri, "languageId": "yaml", "text": editedDoc},
+	}, false)
+	c.send("textDocument/completion", at(11, 8), true)
+	c.send("textDocument/definition", at(8, 9), true)
+	refs := at(3, 7)
+	refs["textDocument"] = map[string]string{"uri": baseURI}
+	refs["position"] = map[string]int{"line": 3, "character": 7}
+	refs["context"] = map[string]bool{"includeDeclaration": false}
+	c.send("textDocument/references", refs, true)
+	c.send("textDocument/hover", at(3, 8), true)
+	c.send("textDocument/didChange", map[string]interface{}{
+		"textDocument":   doc,
+		"contentChanges": []map[string]string{{"text": "version: 1\nkind: components\n"}},
+	}, false)
+	c.send("foo/bar", map[string]string{}, true)
+	c.send("shutdown", nil, true)
+	c.send("exit", nil, false)
+
+	var out bytes.Buffer
+	err = Serve(&c.in, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := map[string]*json.RawMessage{}
+	diags := [][]Diagnostic{}
+	errs := map[string]*responseError{}
+	conn := newConn(&out, nil)
+	for {
+		msg, err := conn.read()
+		if err != nil {
+			break
+		}
+		if msg.Method == "textDocument/publishDiagnostics" {
+			p := &publishDiagnosticsParams{}
+			json.Unmarshal(*msg.Params, p)
+			diags = append(diags, p.Diagnostics)
+			continue
+		}
+		var id int
+		json.Unmarshal(*msg.ID, &id)
+		if msg.Error != nil {
+			errs[c.ids[id]] = msg.Error
+			continue
+		}
+		data, _ := json.Marshal(msg.Result)
+		raw := json.RawMessage(data)
+		results[c.ids[id]] = &raw
+	}
+
+	t.Run("diagnostics", func(t *testing.T) {
+		if len(diags) != 2 {
+			t.Fatalf("diagnostics must be published on open and change; got: %v", diags)
+		}
+		messages := []string{}
+		for _, d := range diags[0] {
+			messages = append(messages, d.Message)
+		}
+		want := []string{"`dependencies[].id` must be specified", "the component `cache` is undefined"}
+		if !reflect.DeepEqual(messages, want) {
+			t.Errorf("unexpected diagnostics; want: %v, got: %v", want, messages)
+		}
+		if len(diags[1]) != 1 || diags[1][0].Severity != severityError || !strings.Contains(diags[1][0].Message, "`components`") {
+			t.Errorf("a validation error must be reported; got: %v", diags[1])
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		items := []completionItem{}
+		json.Unmarshal(*results["textDocument/completion"], &items)
+		labels := []string{}
+		for _, i := range items {
+			labels = append(labels, i.Label)
+		}
+		if want := []string{"db", "payments", "service"}; !reflect.DeepEqual(labels, want) {
+			t.Errorf("unexpected items; want: %v, got: %v", want, labels)
+		}
+	})
+
+	t.Run("definition", func(t *testing.T) {
+		locs := []Location{}
+		json.Unmarshal(*results["textDocument/definition"], &locs)
+		want := []Location{{URI: baseURI, Range: Range{Start: Position{Line: 7, Character: 6}, End: Position{Line: 7, Character: 8}}}}
+		if !reflect.DeepEqual(locs, want) {
+			t.Errorf("unexpected locations; want: %v, got: %v", want, locs)
+		}
+	})
+
+	t.Run("references", func(t *testing.T) {
+		locs := []Location{}
+		json.Unmarshal(*results["textDocument/references"], &locs)
+		lines := []string{}
+		for _, l := range locs {
+			lines = append(lines, fmt.Sprintf("%s:%d", filepath.Base(uriToPath(l.URI)), l.Range.Start.Line))
+		}
+		if want := []string{"base.yaml:8", "payments.yaml:4"}; !reflect.DeepEqual(lines, want) {
+			t.Errorf("unexpected references; want: %v, got: %v", want, lines)
+		}
+	})
+
+	t.Run("hover", func(t *testing.T) {
+		h := &hover{}
+		json.Unmarshal(*results["textDocument/hover"], h)
+		for _, s := range []string{
+			"**payments**",
+			"base: `service`",
+			"- `team`: `core` (inherited from `service`)",
+			"- `tier`: `critical`\n",
+			"dependencies: 3, dependents: 0",
+		} {
+			if !strings.Contains(h.Contents.Value, s) {
+				t.Errorf("hover must contain `%s`; got:\n%s", s, h.Contents.Value)
+			}
+		}
+	})
+
+	t.Run("unsupported method", func(t *testing.T) {
+		if e, ok := errs["foo/bar"]; !ok || e.Code != codeMethodNotFound {
+			t.Errorf("an error must be returned; got: %v", e)
+		}
+		if _, ok := results["shutdown"]; !ok {
+			t.Error("shutdown must be responded")
+		}
+	})
+}
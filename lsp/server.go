@@ -0,0 +1,489 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nihei9/felipe/definitions"
+	"gopkg.in/yaml.v2"
+)
+
+const diagnosticSource = "felipe"
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+):`)
+
+// Server is a language server of definition files. It indexes `*.yaml` files directly under the root
+// directory of the workspace, and documents opened in the editor take precedence over the files.
+type Server struct {
+	conn *conn
+	root string
+	docs map[string]string
+}
+
+// Serve runs a language server that communicates over r and w until the client requests it to exit.
+func Serve(r io.Reader, w io.Writer) error {
+	s := &Server{
+		conn: newConn(r, w),
+		docs: map[string]string{},
+	}
+
+	for {
+		msg, err := s.conn.read()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+
+		result, rErr := s.handle(msg)
+		if msg.ID == nil {
+			continue
+		}
+		res := &message{
+			ID: msg.ID,
+		}
+		if rErr != nil {
+			res.Error = rErr
+		} else if result == nil {
+			res.Result = json.RawMessage("null")
+		} else {
+			res.Result = result
+		}
+		err = s.conn.write(res)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(msg *message) (interface{}, *responseError) {
+	unmarshal := func(v interface{}) *responseError {
+		if msg.Params == nil {
+			return &responseError{Code: codeInvalidParams, Message: "params must be specified"}
+		}
+		err := json.Unmarshal(*msg.Params, v)
+		if err != nil {
+			return &responseError{Code: codeParseError, Message: err.Error()}
+		}
+		return nil
+	}
+
+	switch msg.Method {
+	case "initialize":
+		params := &initializeParams{}
+		if err := unmarshal(params); err != nil {
+			return nil, err
+		}
+		if params.RootURI != "" {
+			s.root = uriToPath(params.RootURI)
+		}
+		return &initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncKindFull,
+				CompletionProvider: &completionOptions{
+					TriggerCharacters: []string{" "},
+				},
+				DefinitionProvider: true,
+				ReferencesProvider: true,
+				HoverProvider:      true,
+			},
+		}, nil
+	case "shutdown":
+		return nil, nil
+	case "textDocument/didOpen":
+		params := &didOpenTextDocumentParams{}
+		if err := unmarshal(params); err != nil {
+			return nil, err
+		}
+		s.docs[params.TextDocument.URI] = params.TextDocument.Text
+		s.publishDiagnostics()
+		return nil, nil
+	case "textDocument/didChange":
+		params := &didChangeTextDocumentParams{}
+		if err := unmarshal(params); err != nil {
+			return nil, err
+		}
+		if n := len(params.ContentChanges); n > 0 {
+			s.docs[params.TextDocument.URI] = params.ContentChanges[n-1].Text
+		}
+		s.publishDiagnostics()
+		return nil, nil
+	case "textDocument/didClose":
+		params := &didCloseTextDocumentParams{}
+		if err := unmarshal(params); err != nil {
+			return nil, err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		s.conn.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+		s.publishDiagnostics()
+		return nil, nil
+	case "textDocument/completion":
+		params := &textDocumentPositionParams{}
+		if err := unmarshal(params); err != nil {
+			return nil, err
+		}
+		return s.completion(params), nil
+	case "textDocument/definition":
+		params := &textDocumentPositionParams{}
+		if err := unmarshal(params); err != nil {
+			return nil, err
+		}
+		return s.definition(params), nil
+	case "textDocument/references":
+		params := &referenceParams{}
+		if err := unmarshal(params); err != nil {
+			return nil, err
+		}
+		return s.references(params), nil
+	case "textDocument/hover":
+		params := &textDocumentPositionParams{}
+		if err := unmarshal(params); err != nil {
+			return nil, err
+		}
+		return s.hover(params), nil
+	}
+
+	if msg.ID != nil {
+		return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method `%s` is not supported", msg.Method)}
+	}
+	return nil, nil
+}
+
+// workspace returns texts of documents by URIs.
+func (s *Server) workspace() map[string]string {
+	docs := map[string]string{}
+	if s.root != "" {
+		files, _ := filepath.Glob(filepath.Join(s.root, "*.yaml"))
+		for _, f := range files {
+			data, err := ioutil.ReadFile(f)
+			if err != nil {
+				continue
+			}
+			docs[pathToURI(f)] = string(data)
+		}
+	}
+	for uri, text := range s.docs {
+		docs[uri] = text
+	}
+
+	return docs
+}
+
+// index returns symbols of components definitions in the workspace.
+func (s *Server) index() map[string][]*symbol {
+	idx := map[string][]*symbol{}
+	for uri, text := range s.workspace() {
+		if kindOf(text) != definitions.DefinitionKindComponents {
+			continue
+		}
+		idx[uri] = scan(text)
+	}
+
+	return idx
+}
+
+// kindOf returns the value of the top-level `kind` key without parsing the whole document.
+func kindOf(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "kind:") {
+			v, _ := unquoteValue(strings.TrimPrefix(line, "kind:"))
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func (s *Server) publishDiagnostics() {
+	idx := s.index()
+	defined := map[string]int{}
+	for _, syms := range idx {
+		for _, sym := range syms {
+			if sym.kind == symbolDefinition {
+				defined[sym.id]++
+			}
+		}
+	}
+
+	uris := []string{}
+	for uri := range s.docs {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		diags := validate(s.docs[uri])
+		for _, sym := range idx[uri] {
+			switch {
+			case sym.kind == symbolDefinition && defined[sym.id] > 1:
+				diags = append(diags, Diagnostic{
+					Range:    sym.rng,
+					Severity: severityWarning,
+					Source:   diagnosticSource,
+					Message:  fmt.Sprintf("the component `%s` is defined more than once", sym.id),
+				})
+			case sym.kind == symbolBase && defined[sym.id] == 0:
+				diags = append(diags, Diagnostic{
+					Range:    sym.rng,
+					Severity: severityError,
+					Source:   diagnosticSource,
+					Message:  fmt.Sprintf("the base component `%s` is undefined", sym.id),
+				})
+			case sym.kind == symbolDependency && defined[sym.id] == 0:
+				diags = append(diags, Diagnostic{
+					Range:    sym.rng,
+					Severity: severityInformation,
+					Source:   diagnosticSource,
+					Message:  fmt.Sprintf("the component `%s` is undefined", sym.id),
+				})
+			}
+		}
+		s.conn.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{
+			URI:         uri,
+			Diagnostics: diags,
+		})
+	}
+}
+
+// validate validates a document with the reader of its kind. Validators do not know positions, so
+// their errors are reported on the first line except for YAML syntax errors.
+func validate(text string) []Diagnostic {
+	var err error
+	switch kindOf(text) {
+	case definitions.DefinitionKindFaces:
+		_, err = definitions.ReadFacesDefinition(strings.NewReader(text))
+	case definitions.DefinitionKindAliases:
+		_, err = definitions.ReadAliasesDefinition(strings.NewReader(text))
+	case definitions.DefinitionKindViews:
+		_, err = definitions.ReadViewsDefinition(strings.NewReader(text))
+	case definitions.DefinitionKindOverlay:
+		_, err = definitions.ReadOverlayDefinition(strings.NewReader(text))
+	default:
+		_, err = definitions.ReadComponentsDefinition(strings.NewReader(text))
+	}
+	if err == nil {
+		return []Diagnostic{}
+	}
+
+	line := 0
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		line = n - 1
+	}
+	lines := strings.Split(text, "\n")
+	end := 0
+	if line < len(lines) {
+		l := strings.TrimRight(lines[line], "\r")
+		end = utf16Offset(l, len(l))
+	}
+
+	return []Diagnostic{
+		{
+			Range: Range{
+				Start: Position{Line: line},
+				End:   Position{Line: line, Character: end},
+			},
+			Severity: severityError,
+			Source:   diagnosticSource,
+			Message:  err.Error(),
+		},
+	}
+}
+
+func symbolAt(idx map[string][]*symbol, uri string, pos Position) *symbol {
+	for _, sym := range idx[uri] {
+		if sym.rng.contains(pos) {
+			return sym
+		}
+	}
+	return nil
+}
+
+func (s *Server) completion(params *textDocumentPositionParams) []completionItem {
+	items := []completionItem{}
+	kind, ok := kindAt(s.workspace()[params.TextDocument.URI], params.Position)
+	if !ok || kind == symbolDefinition {
+		return items
+	}
+
+	seen := map[string]bool{}
+	for uri, syms := range s.index() {
+		for _, sym := range syms {
+			if sym.kind != symbolDefinition || seen[sym.id] {
+				continue
+			}
+			seen[sym.id] = true
+			items = append(items, completionItem{
+				Label:  sym.id,
+				Kind:   completionItemKindReference,
+				Detail: filepath.Base(uriToPath(uri)),
+			})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+
+	return items
+}
+
+func (s *Server) definition(params *textDocumentPositionParams) []Location {
+	idx := s.index()
+	locs := []Location{}
+	sym := symbolAt(idx, params.TextDocument.URI, params.Position)
+	if sym == nil {
+		return locs
+	}
+
+	return find(idx, sym.id, func(k symbolKind) bool {
+		return k == symbolDefinition
+	})
+}
+
+// references returns components that depend on or inherit the component at the position.
+func (s *Server) references(params *referenceParams) []Location {
+	idx := s.index()
+	sym := symbolAt(idx, params.TextDocument.URI, params.Position)
+	if sym == nil {
+		return []Location{}
+	}
+
+	return find(idx, sym.id, func(k symbolKind) bool {
+		return k != symbolDefinition || params.Context.IncludeDeclaration
+	})
+}
+
+func find(idx map[string][]*symbol, id string, match func(symbolKind) bool) []Location {
+	uris := []string{}
+	for uri := range idx {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	locs := []Location{}
+	for _, uri := range uris {
+		for _, sym := range idx[uri] {
+			if sym.id == id && match(sym.kind) {
+				locs = append(locs, Location{URI: uri, Range: sym.rng})
+			}
+		}
+	}
+
+	return locs
+}
+
+// hover shows labels of the component at the position including labels inherited from its bases.
+func (s *Server) hover(params *textDocumentPositionParams) *hover {
+	idx := s.index()
+	sym := symbolAt(idx, params.TextDocument.URI, params.Position)
+	if sym == nil {
+		return nil
+	}
+
+	defs := map[string]*definitions.Component{}
+	for _, text := range s.workspace() {
+		if kindOf(text) != definitions.DefinitionKindComponents {
+			continue
+		}
+		// Documents being edited are often invalid, so they are decoded without validation.
+		def := &definitions.ComponentsDefinition{}
+		err := yaml.Unmarshal([]byte(text), def)
+		if err != nil {
+			continue
+		}
+		for _, c := range def.Components {
+			if c == nil {
+				continue
+			}
+			defs[c.ID] = c
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**\n", sym.id)
+	c, ok := defs[sym.id]
+	if !ok {
+		fmt.Fprintf(&b, "\nundefined component\n")
+		return &hover{
+			Contents: markupContent{Kind: "markdown", Value: b.String()},
+			Range:    &sym.rng,
+		}
+	}
+
+	type origin struct {
+		value string
+		from  string
+	}
+	labels := map[string]origin{}
+	visited := map[string]bool{}
+	for cur := c; cur != nil && !visited[cur.ID]; cur = defs[cur.Base] {
+		visited[cur.ID] = true
+		for k, v := range cur.Labels {
+			if _, ok := labels[k]; !ok {
+				labels[k] = origin{value: v, from: cur.ID}
+			}
+		}
+	}
+
+	if c.Base != "" {
+		fmt.Fprintf(&b, "\nbase: `%s`\n", c.Base)
+	}
+	if len(labels) > 0 {
+		keys := []string{}
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(&b, "\nlabels:\n")
+		for _, k := range keys {
+			o := labels[k]
+			if o.from == c.ID {
+				fmt.Fprintf(&b, "- `%s`: `%s`\n", k, o.value)
+			} else {
+				fmt.Fprintf(&b, "- `%s`: `%s` (inherited from `%s`)\n", k, o.value, o.from)
+			}
+		}
+	}
+	dependents := find(idx, sym.id, func(k symbolKind) bool {
+		return k == symbolDependency
+	})
+	fmt.Fprintf(&b, "\ndependencies: %d, dependents: %d\n", len(c.Dependencies), len(dependents))
+
+	return &hover{
+		Contents: markupContent{Kind: "markdown", Value: b.String()},
+		Range:    &sym.rng,
+	}
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func pathToURI(path string) string {
+	abs, err := filepath.Abs(path)
+	if err == nil {
+		path = abs
+	}
+	u := url.URL{
+		Scheme: "file",
+		Path:   filepath.ToSlash(path),
+	}
+	return u.String()
+}
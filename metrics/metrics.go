@@ -0,0 +1,306 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nihei9/felipe/component"
+)
+
+const (
+	LabelFanIn       = "metrics.fan_in"
+	LabelFanOut      = "metrics.fan_out"
+	LabelInstability = "metrics.instability"
+	LabelDepth       = "metrics.depth"
+	LabelBetweenness = "metrics.betweenness"
+
+	LabelFanInLevel       = "metrics.fan_in_level"
+	LabelFanOutLevel      = "metrics.fan_out_level"
+	LabelInstabilityLevel = "metrics.instability_level"
+	LabelDepthLevel       = "metrics.depth_level"
+	LabelBetweennessLevel = "metrics.betweenness_level"
+)
+
+// Levels classify metrics so that faces can select components by exact labels such as
+// `metrics.betweenness_level: high`.
+const (
+	LevelHigh   = "high"
+	LevelMedium = "medium"
+	LevelLow    = "low"
+)
+
+type Metrics struct {
+	ID component.ComponentID
+
+	// FanIn is the number of components that depend on the component (afferent couplings; Ca).
+	FanIn int
+
+	// FanOut is the number of components the component depends on (efferent couplings; Ce).
+	FanOut int
+
+	// Instability is Ce/(Ca+Ce). It is 0 when the component has no coupling.
+	Instability float64
+
+	// Depth is the length of the longest dependency chain that starts from the component.
+	// Components in the same cycle are regarded as one component.
+	Depth int
+
+	// Betweenness is the betweenness centrality of the component.
+	Betweenness float64
+}
+
+func (m *Metrics) Labels() map[string]string {
+	return map[string]string{
+		LabelFanIn:       fmt.Sprintf("%d", m.FanIn),
+		LabelFanOut:      fmt.Sprintf("%d", m.FanOut),
+		LabelInstability: fmt.Sprintf("%.2f", m.Instability),
+		LabelDepth:       fmt.Sprintf("%d", m.Depth),
+		LabelBetweenness: fmt.Sprintf("%.2f", m.Betweenness),
+	}
+}
+
+// LevelLabels classifies metrics of each component into LevelHigh, LevelMedium and LevelLow. A value is
+// high when it is at least two thirds of the largest value among ms, and medium when it is at least
+// one third. Instability is classified on its own scale from 0 to 1.
+func LevelLabels(ms []*Metrics) map[component.ComponentID]map[string]string {
+	var maxFanIn, maxFanOut, maxDepth, maxBetweenness float64
+	for _, m := range ms {
+		maxFanIn = math.Max(maxFanIn, float64(m.FanIn))
+		maxFanOut = math.Max(maxFanOut, float64(m.FanOut))
+		maxDepth = math.Max(maxDepth, float64(m.Depth))
+		maxBetweenness = math.Max(maxBetweenness, m.Betweenness)
+	}
+
+	labels := map[component.ComponentID]map[string]string{}
+	for _, m := range ms {
+		labels[m.ID] = map[string]string{
+			LabelFanInLevel:       level(float64(m.FanIn), maxFanIn),
+			LabelFanOutLevel:      level(float64(m.FanOut), maxFanOut),
+			LabelInstabilityLevel: level(m.Instability, 1),
+			LabelDepthLevel:       level(float64(m.Depth), maxDepth),
+			LabelBetweennessLevel: level(m.Betweenness, maxBetweenness),
+		}
+	}
+
+	return labels
+}
+
+func level(v float64, max float64) string {
+	switch {
+	case max <= 0 || v < max/3:
+		return LevelLow
+	case v < max*2/3:
+		return LevelMedium
+	default:
+		return LevelHigh
+	}
+}
+
+// Compute calculates metrics of each component in the order of cs.GetIDs().
+// Dependencies on components not contained in cs are ignored.
+func Compute(cs *component.Components) []*Metrics {
+	g := newGraph(cs)
+
+	ms := make([]*Metrics, len(g.nodes))
+	for i, id := range g.nodes {
+		ms[i] = &Metrics{
+			ID: id,
+		}
+	}
+	for u, vs := range g.edges {
+		ms[u].FanOut = len(vs)
+		for _, v := range vs {
+			ms[v].FanIn++
+		}
+	}
+	for _, m := range ms {
+		if m.FanIn+m.FanOut > 0 {
+			m.Instability = float64(m.FanOut) / float64(m.FanIn+m.FanOut)
+		}
+	}
+	for i, d := range g.depths() {
+		ms[i].Depth = d
+	}
+	for i, b := range g.betweenness() {
+		ms[i].Betweenness = b
+	}
+
+	return ms
+}
+
+type graph struct {
+	nodes []component.ComponentID
+	edges [][]int
+}
+
+func newGraph(cs *component.Components) *graph {
+	ids := cs.GetIDs()
+	index := map[component.ComponentID]int{}
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	g := &graph{
+		nodes: ids,
+		edges: make([][]int, len(ids)),
+	}
+	for i, id := range ids {
+		c, _ := cs.Get(id)
+		for depID := range c.Dependencies {
+			j, ok := index[depID]
+			if !ok || j == i {
+				continue
+			}
+			g.edges[i] = append(g.edges[i], j)
+		}
+	}
+
+	return g
+}
+
+// depths calculates the longest path from each node over the condensation of the graph.
+func (g *graph) depths() []int {
+	scc := g.stronglyConnectedComponents()
+
+	memo := map[int]int{}
+	var depth func(s int) int
+	depth = func(s int) int {
+		if d, ok := memo[s]; ok {
+			return d
+		}
+		d := 0
+		for _, u := range scc.members[s] {
+			for _, v := range g.edges[u] {
+				t := scc.of[v]
+				if t == s {
+					continue
+				}
+				if dd := depth(t) + 1; dd > d {
+					d = dd
+				}
+			}
+		}
+		memo[s] = d
+		return d
+	}
+
+	ds := make([]int, len(g.nodes))
+	for u := range g.nodes {
+		ds[u] = depth(scc.of[u])
+	}
+
+	return ds
+}
+
+type sccs struct {
+	of      []int
+	members [][]int
+}
+
+// stronglyConnectedComponents finds strongly connected components using Tarjan's algorithm.
+func (g *graph) stronglyConnectedComponents() *sccs {
+	n := len(g.nodes)
+	result := &sccs{
+		of: make([]int, n),
+	}
+	index := make([]int, n)
+	lowLink := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = -1
+	}
+	stack := []int{}
+	next := 0
+
+	var visit func(u int)
+	visit = func(u int) {
+		index[u] = next
+		lowLink[u] = next
+		next++
+		stack = append(stack, u)
+		onStack[u] = true
+
+		for _, v := range g.edges[u] {
+			if index[v] < 0 {
+				visit(v)
+				if lowLink[v] < lowLink[u] {
+					lowLink[u] = lowLink[v]
+				}
+			} else if onStack[v] && index[v] < lowLink[u] {
+				lowLink[u] = index[v]
+			}
+		}
+
+		if lowLink[u] != index[u] {
+			return
+		}
+		members := []int{}
+		for {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[v] = false
+			result.of[v] = len(result.members)
+			members = append(members, v)
+			if v == u {
+				break
+			}
+		}
+		result.members = append(result.members, members)
+	}
+
+	for u := 0; u < n; u++ {
+		if index[u] < 0 {
+			visit(u)
+		}
+	}
+
+	return result
+}
+
+// betweenness calculates betweenness centrality using Brandes' algorithm.
+func (g *graph) betweenness() []float64 {
+	n := len(g.nodes)
+	cb := make([]float64, n)
+	for s := 0; s < n; s++ {
+		stack := []int{}
+		preds := make([][]int, n)
+		sigma := make([]float64, n)
+		dist := make([]int, n)
+		for i := range dist {
+			dist[i] = -1
+		}
+		sigma[s] = 1
+		dist[s] = 0
+
+		queue := []int{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, w := range g.edges[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+
+		delta := make([]float64, n)
+		for len(stack) > 0 {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				cb[w] += delta[w]
+			}
+		}
+	}
+
+	return cb
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+)
+
+func TestCompute(t *testing.T) {
+	// a -> b -> c -> d
+	//      b -> e -> b (cycle)
+	//      a -> x (undefined)
+	cs := component.NewComponents()
+	for _, e := range []struct {
+		id   component.ComponentID
+		deps []component.ComponentID
+	}{
+		{id: "a", deps: []component.ComponentID{"b", "x"}},
+		{id: "b", deps: []component.ComponentID{"c", "e"}},
+		{id: "c", deps: []component.ComponentID{"d"}},
+		{id: "d"},
+		{id: "e", deps: []component.ComponentID{"b"}},
+	} {
+		c := component.NewComponent(component.NilComponentID, e.id)
+		for _, d := range e.deps {
+			c.DependOn(d, &component.Relation{})
+		}
+		cs.Add(c)
+	}
+
+	want := map[component.ComponentID]*Metrics{
+		"a": {FanIn: 0, FanOut: 1, Instability: 1, Depth: 3, Betweenness: 0},
+		"b": {FanIn: 2, FanOut: 2, Instability: 0.5, Depth: 2, Betweenness: 5},
+		"c": {FanIn: 1, FanOut: 1, Instability: 0.5, Depth: 1, Betweenness: 3},
+		"d": {FanIn: 1, FanOut: 0, Instability: 0, Depth: 0, Betweenness: 0},
+		"e": {FanIn: 1, FanOut: 1, Instability: 0.5, Depth: 2, Betweenness: 0},
+	}
+
+	ms := Compute(cs)
+	if len(ms) != len(want) {
+		t.Fatalf("unexpected metrics count; want: %v, got: %v", len(want), len(ms))
+	}
+	for _, m := range ms {
+		w := want[m.ID]
+		w.ID = m.ID
+		if *m != *w {
+			t.Errorf("unexpected metrics; want: %+v, got: %+v", w, m)
+		}
+	}
+}
+
+func TestLevelLabels(t *testing.T) {
+	ms := []*Metrics{
+		{ID: "a", FanIn: 0, FanOut: 3, Instability: 1, Depth: 2, Betweenness: 0},
+		{ID: "b", FanIn: 3, FanOut: 1, Instability: 0.25, Depth: 1, Betweenness: 6},
+		{ID: "c", FanIn: 1, FanOut: 0, Instability: 0, Depth: 0, Betweenness: 2},
+	}
+	want := map[component.ComponentID]map[string]string{
+		"a": {
+			LabelFanInLevel:       LevelLow,
+			LabelFanOutLevel:      LevelHigh,
+			LabelInstabilityLevel: LevelHigh,
+			LabelDepthLevel:       LevelHigh,
+			LabelBetweennessLevel: LevelLow,
+		},
+		"b": {
+			LabelFanInLevel:       LevelHigh,
+			LabelFanOutLevel:      LevelMedium,
+			LabelInstabilityLevel: LevelLow,
+			LabelDepthLevel:       LevelMedium,
+			LabelBetweennessLevel: LevelHigh,
+		},
+		"c": {
+			LabelFanInLevel:       LevelMedium,
+			LabelFanOutLevel:      LevelLow,
+			LabelInstabilityLevel: LevelLow,
+			LabelDepthLevel:       LevelLow,
+			LabelBetweennessLevel: LevelMedium,
+		},
+	}
+
+	got := LevelLabels(ms)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected labels; want: %v, got: %v", want, got)
+	}
+}
@@ -0,0 +1,134 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nihei9/felipe/component"
+)
+
+type CycleError struct {
+	Cycle []component.ComponentID
+}
+
+func (e *CycleError) Error() string {
+	ids := []string{}
+	for _, id := range e.Cycle {
+		ids = append(ids, id.String())
+	}
+	return fmt.Sprintf("components cannot be ordered because of the cyclic dependency: %s", strings.Join(ids, " -> "))
+}
+
+// Waves sorts components topologically and groups them into waves. Every component
+// in a wave depends only on components in the preceding waves, so components in
+// the same wave can be deployed or started in parallel. Dependencies on components
+// not contained in cs are ignored, so cs should be the whole graph; use Select to
+// narrow the result down. When the components have a cyclic dependency, Waves
+// returns *CycleError.
+func Waves(cs *component.Components) ([][]component.ComponentID, error) {
+	deps := map[component.ComponentID][]component.ComponentID{}
+	dependents := map[component.ComponentID][]component.ComponentID{}
+	remaining := map[component.ComponentID]int{}
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		remaining[id] = 0
+		for depID := range c.Dependencies {
+			if _, ok := cs.Get(depID); !ok || depID == id {
+				continue
+			}
+			deps[id] = append(deps[id], depID)
+			dependents[depID] = append(dependents[depID], id)
+			remaining[id]++
+		}
+	}
+
+	waves := [][]component.ComponentID{}
+	wave := []component.ComponentID{}
+	for _, id := range cs.GetIDs() {
+		if remaining[id] == 0 {
+			wave = append(wave, id)
+		}
+	}
+	for len(wave) > 0 {
+		sortIDs(wave)
+		waves = append(waves, wave)
+
+		next := []component.ComponentID{}
+		for _, id := range wave {
+			delete(remaining, id)
+			for _, dependent := range dependents[id] {
+				remaining[dependent]--
+				if remaining[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		wave = next
+	}
+
+	if len(remaining) > 0 {
+		return nil, &CycleError{
+			Cycle: findCycle(remaining, deps),
+		}
+	}
+
+	return waves, nil
+}
+
+// Select narrows waves down to components contained in cs. Waves that become empty
+// are removed. Because the waves are computed beforehand, components keep the order
+// implied by dependencies through components that are not selected.
+func Select(waves [][]component.ComponentID, cs *component.Components) [][]component.ComponentID {
+	selected := [][]component.ComponentID{}
+	for _, wave := range waves {
+		w := []component.ComponentID{}
+		for _, id := range wave {
+			if _, ok := cs.Get(id); ok {
+				w = append(w, id)
+			}
+		}
+		if len(w) > 0 {
+			selected = append(selected, w)
+		}
+	}
+
+	return selected
+}
+
+// findCycle finds a cycle among components that could not be ordered. Each of them
+// depends on at least one other unordered component, so following such dependencies
+// always reaches a component visited before.
+func findCycle(unordered map[component.ComponentID]int, deps map[component.ComponentID][]component.ComponentID) []component.ComponentID {
+	ids := []component.ComponentID{}
+	for id := range unordered {
+		ids = append(ids, id)
+	}
+	sortIDs(ids)
+
+	path := []component.ComponentID{}
+	visited := map[component.ComponentID]int{}
+	id := ids[0]
+	for {
+		if i, ok := visited[id]; ok {
+			return append(path[i:], id)
+		}
+		visited[id] = len(path)
+		path = append(path, id)
+
+		ds := append([]component.ComponentID{}, deps[id]...)
+		sortIDs(ds)
+		for _, d := range ds {
+			if _, ok := unordered[d]; ok {
+				id = d
+				break
+			}
+		}
+	}
+}
+
+func sortIDs(ids []component.ComponentID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+}
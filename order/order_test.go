@@ -0,0 +1,100 @@
+package order
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+)
+
+func TestWaves(t *testing.T) {
+	tests := []struct {
+		caption string
+		deps    map[component.ComponentID][]component.ComponentID
+		waves   [][]component.ComponentID
+		cycle   []component.ComponentID
+	}{
+		{
+			caption: "components are grouped into waves",
+			deps: map[component.ComponentID][]component.ComponentID{
+				"web":   {"api", "cdn"},
+				"api":   {"db", "cache", "external"},
+				"db":    nil,
+				"cache": nil,
+				"cdn":   nil,
+			},
+			waves: [][]component.ComponentID{
+				{"cache", "cdn", "db"},
+				{"api"},
+				{"web"},
+			},
+		},
+		{
+			caption: "a cyclic dependency is reported",
+			deps: map[component.ComponentID][]component.ComponentID{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {"b", "d"},
+				"d": nil,
+			},
+			cycle: []component.ComponentID{"b", "c", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			cs := component.NewComponents()
+			for id, deps := range tt.deps {
+				c := component.NewComponent(component.NilComponentID, id)
+				for _, d := range deps {
+					c.DependOn(d, &component.Relation{})
+				}
+				cs.Add(c)
+			}
+
+			waves, err := Waves(cs)
+			if tt.cycle != nil {
+				cErr, ok := err.(*CycleError)
+				if !ok {
+					t.Fatalf("unexpected error; want: *CycleError, got: %v", err)
+				}
+				if !reflect.DeepEqual(cErr.Cycle, tt.cycle) {
+					t.Errorf("unexpected cycle; want: %v, got: %v", tt.cycle, cErr.Cycle)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(waves, tt.waves) {
+				t.Errorf("unexpected waves; want: %v, got: %v", tt.waves, waves)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	// a -> b -> c
+	cs := component.NewComponents()
+	a := component.NewComponent(component.NilComponentID, "a")
+	a.DependOn("b", &component.Relation{})
+	cs.Add(a)
+	b := component.NewComponent(component.NilComponentID, "b")
+	b.DependOn("c", &component.Relation{})
+	cs.Add(b)
+	c := component.NewComponent(component.NilComponentID, "c")
+	cs.Add(c)
+
+	waves, err := Waves(cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selected := component.NewComponents()
+	selected.Add(a)
+	selected.Add(c)
+	want := [][]component.ComponentID{{"c"}, {"a"}}
+	got := Select(waves, selected)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("an order through an unselected component must be kept; want: %v, got: %v", want, got)
+	}
+}
@@ -0,0 +1,88 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nihei9/felipe/component"
+)
+
+// ParseFilter parses a filter such as `key=value`.
+func ParseFilter(s string) (Filter, error) {
+	f := strings.Split(s, "=")
+	if len(f) != 2 {
+		return nil, fmt.Errorf("filter is malformed; got: %v", s)
+	}
+	k := strings.TrimSpace(f[0])
+	v := strings.TrimSpace(f[1])
+
+	return LabelsFilter{
+		Labels: map[string]string{k: v},
+	}, nil
+}
+
+// ParseComplementation parses complementations such as `dep=2,rdep=1>dep=1`.
+// Terms separated by `,` are applied to the same components and merged, and
+// terms separated by `>` are applied to the result of the preceding term.
+func ParseComplementation(s string, cs *component.Components, traversal Traversal) (Complementer, error) {
+	union := []Complementer{}
+	for _, u := range strings.Split(s, ",") {
+		chain := []Complementer{}
+		for _, term := range strings.Split(u, ">") {
+			c, err := parseComplementationTerm(term, cs, traversal)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, c)
+		}
+		if len(chain) == 1 {
+			union = append(union, chain[0])
+		} else {
+			union = append(union, ChainComplementer{
+				Complementers: chain,
+			})
+		}
+	}
+	if len(union) == 1 {
+		return union[0], nil
+	}
+
+	return UnionComplementer{
+		Complementers: union,
+	}, nil
+}
+
+func parseComplementationTerm(term string, cs *component.Components, traversal Traversal) (Complementer, error) {
+	f := strings.Split(term, "=")
+	if len(f) != 2 {
+		return nil, fmt.Errorf("complementation is malformed; got: %v", term)
+	}
+	k := strings.TrimSpace(f[0])
+	v := strings.TrimSpace(f[1])
+
+	switch k {
+	case "dep":
+		depth, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		return DependenciesComplementer{
+			AllComponents: cs,
+			Depth:         depth,
+			Traversal:     traversal,
+		}, nil
+	case "rdep":
+		depth, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		return ReverseDependenciesComplementer{
+			AllComponents: cs,
+			Depth:         depth,
+			Traversal:     traversal,
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid complementation; got: %v", k)
+	}
+}
@@ -0,0 +1,120 @@
+package query
+
+import (
+	"sort"
+
+	"github.com/nihei9/felipe/component"
+)
+
+// DefaultPathLimit is the number of paths FindPaths returns when limit is negative.
+const DefaultPathLimit = 100
+
+// FindPaths returns simple paths of dependencies from a component to another in ascending order of
+// their lengths. At most limit paths are returned, and DefaultPathLimit is used when limit is negative.
+// Paths are searched one length at a time, so the search stops as soon as limit paths are found.
+func FindPaths(cs *component.Components, from component.ComponentID, to component.ComponentID, limit int) [][]component.ComponentID {
+	paths := [][]component.ComponentID{}
+	if _, ok := cs.Get(from); !ok {
+		return paths
+	}
+	if _, ok := cs.Get(to); !ok {
+		return paths
+	}
+	if limit < 0 {
+		limit = DefaultPathLimit
+	}
+	if limit == 0 {
+		return paths
+	}
+
+	deps := sortedDependencies(cs)
+	dist := distancesTo(cs, to)
+	if _, ok := dist[from]; !ok {
+		return paths
+	}
+
+	onPath := map[component.ComponentID]bool{}
+	path := []component.ComponentID{}
+	// walk appends paths whose length is exactly maxLen. Components from which `to` cannot be
+	// reached within the remaining length are not walked.
+	var walk func(id component.ComponentID, maxLen int) bool
+	walk = func(id component.ComponentID, maxLen int) bool {
+		path = append(path, id)
+		onPath[id] = true
+		defer func() {
+			path = path[:len(path)-1]
+			onPath[id] = false
+		}()
+
+		if id == to {
+			if len(path)-1 == maxLen {
+				paths = append(paths, append([]component.ComponentID{}, path...))
+			}
+			return len(paths) >= limit
+		}
+		for _, depID := range deps[id] {
+			d, ok := dist[depID]
+			if !ok || onPath[depID] || len(path)+d > maxLen {
+				continue
+			}
+			if walk(depID, maxLen) {
+				return true
+			}
+		}
+		return false
+	}
+	for maxLen := dist[from]; maxLen < len(cs.GetIDs()); maxLen++ {
+		if walk(from, maxLen) {
+			break
+		}
+	}
+
+	return paths
+}
+
+func sortedDependencies(cs *component.Components) map[component.ComponentID][]component.ComponentID {
+	deps := map[component.ComponentID][]component.ComponentID{}
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		ds := []component.ComponentID{}
+		for depID := range c.Dependencies {
+			if _, ok := cs.Get(depID); ok {
+				ds = append(ds, depID)
+			}
+		}
+		sort.Slice(ds, func(i, j int) bool {
+			return ds[i] < ds[j]
+		})
+		deps[id] = ds
+	}
+
+	return deps
+}
+
+// distancesTo returns the lengths of the shortest paths from components to a component.
+// Components that cannot reach it are not included.
+func distancesTo(cs *component.Components, to component.ComponentID) map[component.ComponentID]int {
+	dependents := map[component.ComponentID][]component.ComponentID{}
+	for _, id := range cs.GetIDs() {
+		c, _ := cs.Get(id)
+		for depID := range c.Dependencies {
+			dependents[depID] = append(dependents[depID], id)
+		}
+	}
+
+	dist := map[component.ComponentID]int{to: 0}
+	queue := []component.ComponentID{to}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[id] {
+			if _, ok := dist[dependent]; ok {
+				continue
+			}
+			dist[dependent] = dist[id] + 1
+			queue = append(queue, dependent)
+		}
+	}
+
+	return dist
+}
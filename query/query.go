@@ -1,6 +1,10 @@
 package query
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/nihei9/felipe/component"
 )
 
@@ -8,6 +12,7 @@ type Query struct {
 	Components   *component.Components
 	Filter       Filter
 	Complementer Complementer
+	Transformers []Transformer
 }
 
 func (q Query) Do() (*component.Components, error) {
@@ -16,16 +21,22 @@ func (q Query) Do() (*component.Components, error) {
 		return nil, err
 	}
 
+	result := filteredComponents
 	if q.Complementer != nil {
-		result, err := q.Complementer.Complement(filteredComponents)
+		result, err = q.Complementer.Complement(filteredComponents)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		return result, nil
+	for _, t := range q.Transformers {
+		result, err = t.Transform(result)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return filteredComponents, nil
+	return result, nil
 }
 
 type Passer interface {
@@ -75,6 +86,22 @@ func (f LabelsFilter) Filter(target *component.Components) (*component.Component
 	return filter(f, target)
 }
 
+// String returns labels in the form of `key=value` sorted by keys.
+func (f LabelsFilter) String() string {
+	keys := []string{}
+	for k := range f.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	labels := []string{}
+	for _, k := range keys {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, f.Labels[k]))
+	}
+
+	return strings.Join(labels, ", ")
+}
+
 func (f LabelsFilter) Pass(target *component.Component) (bool, error) {
 	if target.IsHidden() {
 		return false, nil
@@ -92,37 +119,124 @@ type Complementer interface {
 	Complement(target *component.Components) (*component.Components, error)
 }
 
+// UnionComplementer applies every complementer to the same target and merges their results.
+type UnionComplementer struct {
+	Complementers []Complementer
+}
+
+func (c UnionComplementer) Complement(target *component.Components) (*component.Components, error) {
+	result := component.NewComponents()
+	for _, comp := range c.Complementers {
+		cs, err := comp.Complement(target)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range cs.GetIDs() {
+			c, _ := cs.Get(id)
+			result.Add(c)
+		}
+	}
+
+	return result, nil
+}
+
+// ChainComplementer applies complementers in order. Each complementer takes the result of the previous one.
+type ChainComplementer struct {
+	Complementers []Complementer
+}
+
+func (c ChainComplementer) Complement(target *component.Components) (*component.Components, error) {
+	result := target
+	for _, comp := range c.Complementers {
+		cs, err := comp.Complement(result)
+		if err != nil {
+			return nil, err
+		}
+		result = cs
+	}
+
+	return result, nil
+}
+
+// Traversal controls how complementers walk through dependencies.
+type Traversal struct {
+	// Stop makes complementers stop walking at components it passes. The components are included in the result.
+	Stop Passer
+
+	// Skip makes complementers walk through components it passes without including them in the result.
+	Skip Passer
+
+	// KeepTransitiveEdges makes components depend directly, and indirectly, on the components
+	// reachable through skipped components.
+	KeepTransitiveEdges bool
+}
+
+func (t Traversal) stops(c *component.Component) (bool, error) {
+	if t.Stop == nil {
+		return false, nil
+	}
+	return t.Stop.Pass(c)
+}
+
+func (t Traversal) skips(c *component.Component) (bool, error) {
+	if t.Skip == nil {
+		return false, nil
+	}
+	return t.Skip.Pass(c)
+}
+
+func (t Traversal) finish(acc *component.Components, all *component.Components) (*component.Components, error) {
+	if t.Skip == nil || !t.KeepTransitiveEdges {
+		return acc, nil
+	}
+	return bridge(acc, all, t.Skip)
+}
+
 type DependenciesComplementer struct {
 	AllComponents *component.Components
 	Depth         int
+	Traversal     Traversal
 }
 
 func (c DependenciesComplementer) Complement(target *component.Components) (*component.Components, error) {
 	result := component.NewComponents()
+	visited := map[component.ComponentID]int{}
 	for _, id := range target.GetIDs() {
 		startingPoint, _ := target.Get(id)
-		err := c.complement(0, startingPoint, result)
+		err := c.complement(0, startingPoint, true, visited, result)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return result, nil
+	return c.Traversal.finish(result, c.AllComponents)
 }
 
-func (c DependenciesComplementer) complement(depth int, pivot *component.Component, acc *component.Components) error {
+func (c DependenciesComplementer) complement(depth int, pivot *component.Component, start bool, visited map[component.ComponentID]int, acc *component.Components) error {
 	if c.Depth >= 0 && depth > c.Depth {
 		return nil
 	}
-	if _, ok := acc.Get(pivot.ID); ok {
+	// A component reached again within a shorter distance is walked again, so that components
+	// beyond it within the depth are not missed whichever path reaches it first.
+	if d, ok := visited[pivot.ID]; ok && d <= depth {
+		if start {
+			acc.Add(pivot)
+		}
 		return nil
 	}
+	visited[pivot.ID] = depth
 
-	acc.Add(pivot)
+	next, traverse, err := visit(c.Traversal, depth, pivot, start, acc)
+	if err != nil {
+		return err
+	}
+	if !traverse {
+		return nil
+	}
 
 	for depID, _ := range pivot.Dependencies {
 		dep, _ := c.AllComponents.Get(depID)
-		err := c.complement(depth+1, dep, acc)
+		err := c.complement(next, dep, false, visited, acc)
 		if err != nil {
 			return err
 		}
@@ -134,30 +248,44 @@ func (c DependenciesComplementer) complement(depth int, pivot *component.Compone
 type ReverseDependenciesComplementer struct {
 	AllComponents *component.Components
 	Depth         int
+	Traversal     Traversal
 }
 
 func (c ReverseDependenciesComplementer) Complement(target *component.Components) (*component.Components, error) {
 	result := component.NewComponents()
+	visited := map[component.ComponentID]int{}
 	for _, id := range target.GetIDs() {
 		startingPoint, _ := target.Get(id)
-		err := c.complement(0, startingPoint, result)
+		err := c.complement(0, startingPoint, true, visited, result)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return result, nil
+	return c.Traversal.finish(result, c.AllComponents)
 }
 
-func (c ReverseDependenciesComplementer) complement(depth int, pivot *component.Component, acc *component.Components) error {
+func (c ReverseDependenciesComplementer) complement(depth int, pivot *component.Component, start bool, visited map[component.ComponentID]int, acc *component.Components) error {
 	if c.Depth >= 0 && depth > c.Depth {
 		return nil
 	}
-	if _, ok := acc.Get(pivot.ID); ok {
+	// A component reached again within a shorter distance is walked again, so that components
+	// beyond it within the depth are not missed whichever path reaches it first.
+	if d, ok := visited[pivot.ID]; ok && d <= depth {
+		if start {
+			acc.Add(pivot)
+		}
 		return nil
 	}
+	visited[pivot.ID] = depth
 
-	acc.Add(pivot)
+	next, traverse, err := visit(c.Traversal, depth, pivot, start, acc)
+	if err != nil {
+		return err
+	}
+	if !traverse {
+		return nil
+	}
 
 	for _, rDepID := range c.AllComponents.GetIDs() {
 		rDep, _ := c.AllComponents.Get(rDepID)
@@ -165,7 +293,7 @@ func (c ReverseDependenciesComplementer) complement(depth int, pivot *component.
 			if depID != pivot.ID {
 				continue
 			}
-			err := c.complement(depth+1, rDep, acc)
+			err := c.complement(next, rDep, false, visited, acc)
 			if err != nil {
 				return err
 			}
@@ -174,3 +302,229 @@ func (c ReverseDependenciesComplementer) complement(depth int, pivot *component.
 
 	return nil
 }
+
+// visit adds a pivot to the result unless the traversal skips it, and returns the depth of
+// the components next to the pivot and whether the traversal goes beyond the pivot.
+// Skipped components do not count toward the depth. Starting points are neither stopped
+// nor skipped.
+func visit(t Traversal, depth int, pivot *component.Component, start bool, acc *component.Components) (int, bool, error) {
+	if start {
+		acc.Add(pivot)
+		return depth + 1, true, nil
+	}
+
+	skip, err := t.skips(pivot)
+	if err != nil {
+		return 0, false, err
+	}
+	if skip {
+		return depth, true, nil
+	}
+
+	acc.Add(pivot)
+
+	stop, err := t.stops(pivot)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return depth + 1, !stop, nil
+}
+
+// bridge replaces dependencies on skipped components with indirect dependencies on the
+// components in the target reachable through only skipped components.
+func bridge(target *component.Components, all *component.Components, skip Passer) (*component.Components, error) {
+	result := component.NewComponents()
+	for _, id := range target.GetIDs() {
+		c, _ := target.Get(id)
+
+		var bridged *component.Component
+		for depID := range c.Dependencies {
+			if _, ok := target.Get(depID); ok {
+				continue
+			}
+			dep, ok := all.Get(depID)
+			if !ok {
+				continue
+			}
+			skipped, err := skip.Pass(dep)
+			if err != nil {
+				return nil, err
+			}
+			if !skipped {
+				continue
+			}
+
+			if bridged == nil {
+				bridged = c.Clone()
+			}
+			delete(bridged.Dependencies, depID)
+
+			reachable, err := reachThrough(dep, target, all, skip)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range reachable {
+				if r == c.ID {
+					continue
+				}
+				if _, ok := c.Dependencies[r]; ok {
+					continue
+				}
+				bridged.DependOn(r, &component.Relation{
+					Indirect: true,
+				})
+			}
+		}
+
+		if bridged != nil {
+			result.Add(bridged)
+		} else {
+			result.Add(c)
+		}
+	}
+
+	return result, nil
+}
+
+func reachThrough(skipped *component.Component, target *component.Components, all *component.Components, skip Passer) ([]component.ComponentID, error) {
+	reachable := []component.ComponentID{}
+	visited := map[component.ComponentID]bool{
+		skipped.ID: true,
+	}
+	stack := []*component.Component{skipped}
+	for len(stack) > 0 {
+		pivot := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for depID := range pivot.Dependencies {
+			if visited[depID] {
+				continue
+			}
+			visited[depID] = true
+
+			if _, ok := target.Get(depID); ok {
+				reachable = append(reachable, depID)
+				continue
+			}
+			dep, ok := all.Get(depID)
+			if !ok {
+				continue
+			}
+			s, err := skip.Pass(dep)
+			if err != nil {
+				return nil, err
+			}
+			if s {
+				stack = append(stack, dep)
+			}
+		}
+	}
+
+	return reachable, nil
+}
+
+type Transformer interface {
+	Transform(target *component.Components) (*component.Components, error)
+}
+
+// TransitiveReducer removes dependencies implied by longer chains of dependencies
+// among the target components, while preserving reachability between them.
+type TransitiveReducer struct {
+	// PreservedRelations are relation descriptions whose dependencies are never removed.
+	PreservedRelations []string
+}
+
+func (r TransitiveReducer) Transform(target *component.Components) (*component.Components, error) {
+	preserved := map[string]bool{}
+	for _, rel := range r.PreservedRelations {
+		preserved[rel] = true
+	}
+
+	result := component.NewComponents()
+	for _, id := range target.GetIDs() {
+		c, _ := target.Get(id)
+		result.Add(c.Clone())
+	}
+
+	for _, id := range result.GetIDs() {
+		c, _ := result.Get(id)
+		depIDs := []component.ComponentID{}
+		for depID := range c.Dependencies {
+			depIDs = append(depIDs, depID)
+		}
+		sort.Slice(depIDs, func(i, j int) bool {
+			return depIDs[i] < depIDs[j]
+		})
+
+		for _, depID := range depIDs {
+			if _, ok := result.Get(depID); !ok || depID == c.ID {
+				continue
+			}
+			rel := c.Dependencies[depID]
+			if preserved[rel.Description] {
+				continue
+			}
+
+			delete(c.Dependencies, depID)
+			if !reachable(result, c.ID, depID) {
+				c.DependOn(depID, rel)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// reachable reports whether the component identified by to is reachable from the one
+// identified by from through dependencies among the components.
+func reachable(cs *component.Components, from component.ComponentID, to component.ComponentID) bool {
+	visited := map[component.ComponentID]bool{
+		from: true,
+	}
+	stack := []component.ComponentID{from}
+	for len(stack) > 0 {
+		pivot, _ := cs.Get(stack[len(stack)-1])
+		stack = stack[:len(stack)-1]
+		for depID := range pivot.Dependencies {
+			if depID == to {
+				return true
+			}
+			if visited[depID] {
+				continue
+			}
+			if _, ok := cs.Get(depID); !ok {
+				continue
+			}
+			visited[depID] = true
+			stack = append(stack, depID)
+		}
+	}
+
+	return false
+}
+
+// HiddenBypasser removes hidden components from the target. Components that depend on
+// hidden ones depend indirectly on the components reachable through the hidden ones instead.
+type HiddenBypasser struct {
+	AllComponents *component.Components
+}
+
+func (b HiddenBypasser) Transform(target *component.Components) (*component.Components, error) {
+	visible := component.NewComponents()
+	for _, id := range target.GetIDs() {
+		c, _ := target.Get(id)
+		if c.IsHidden() {
+			continue
+		}
+		visible.Add(c)
+	}
+
+	return bridge(visible, b.AllComponents, hiddenPasser{})
+}
+
+type hiddenPasser struct {
+}
+
+func (p hiddenPasser) Pass(target *component.Component) (bool, error) {
+	return target.IsHidden(), nil
+}
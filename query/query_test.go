@@ -0,0 +1,512 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+)
+
+type testComponent struct {
+	id     component.ComponentID
+	labels map[string]string
+	deps   []component.ComponentID
+	hidden bool
+}
+
+// newTestComponents returns the following components.
+//
+//	y -> x -> a -> b -> c -> d
+//
+// `a` has `role=entry`, and `b` and `x` have `kind=library`.
+func newTestComponents() *component.Components {
+	return newComponents([]testComponent{
+		{id: "a", labels: map[string]string{"role": "entry"}, deps: []component.ComponentID{"b"}},
+		{id: "b", labels: map[string]string{"kind": "library"}, deps: []component.ComponentID{"c"}},
+		{id: "c", deps: []component.ComponentID{"d"}},
+		{id: "d"},
+		{id: "x", labels: map[string]string{"kind": "library"}, deps: []component.ComponentID{"a"}},
+		{id: "y", deps: []component.ComponentID{"x"}},
+	})
+}
+
+func newComponents(tcs []testComponent) *component.Components {
+	cs := component.NewComponents()
+	for _, tc := range tcs {
+		c := component.NewComponent(component.NilComponentID, tc.id)
+		for k, v := range tc.labels {
+			c.AddLabel(k, v)
+		}
+		for _, d := range tc.deps {
+			c.DependOn(d, &component.Relation{})
+		}
+		if tc.hidden {
+			c.Hide()
+		}
+		cs.Add(c)
+	}
+
+	return cs
+}
+
+func sortedIDs(cs *component.Components) []component.ComponentID {
+	ids := append([]component.ComponentID{}, cs.GetIDs()...)
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+func TestQuery(t *testing.T) {
+	cs := newTestComponents()
+	entry := LabelsFilter{
+		Labels: map[string]string{"role": "entry"},
+	}
+
+	tests := []struct {
+		caption      string
+		complementer Complementer
+		ids          []component.ComponentID
+	}{
+		{
+			caption: "dependencies",
+			complementer: DependenciesComplementer{
+				AllComponents: cs,
+				Depth:         2,
+			},
+			ids: []component.ComponentID{"a", "b", "c"},
+		},
+		{
+			caption: "reverse dependencies",
+			complementer: ReverseDependenciesComplementer{
+				AllComponents: cs,
+				Depth:         1,
+			},
+			ids: []component.ComponentID{"a", "x"},
+		},
+		{
+			caption: "union of dependencies and reverse dependencies",
+			complementer: UnionComplementer{
+				Complementers: []Complementer{
+					DependenciesComplementer{AllComponents: cs, Depth: 1},
+					ReverseDependenciesComplementer{AllComponents: cs, Depth: 2},
+				},
+			},
+			ids: []component.ComponentID{"a", "b", "x", "y"},
+		},
+		{
+			caption: "chain of dependencies and reverse dependencies",
+			complementer: ChainComplementer{
+				Complementers: []Complementer{
+					ReverseDependenciesComplementer{AllComponents: cs, Depth: 1},
+					DependenciesComplementer{AllComponents: cs, Depth: 2},
+				},
+			},
+			ids: []component.ComponentID{"a", "b", "c", "x"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			result, err := Query{
+				Components:   cs,
+				Filter:       entry,
+				Complementer: tt.complementer,
+			}.Do()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := sortedIDs(result); !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("unexpected components; want: %v, got: %v", tt.ids, ids)
+			}
+		})
+	}
+}
+
+func TestComplementer_shorterPath(t *testing.T) {
+	// z -> a -> b -> c -> d -> e, a -> d
+	cs := newComponents([]testComponent{
+		{id: "z", deps: []component.ComponentID{"a"}},
+		{id: "a", deps: []component.ComponentID{"b", "d"}},
+		{id: "b", deps: []component.ComponentID{"c"}},
+		{id: "c", deps: []component.ComponentID{"d"}},
+		{id: "d", deps: []component.ComponentID{"e"}},
+		{id: "e"},
+	})
+	start := func(id component.ComponentID) *component.Components {
+		c, _ := cs.Get(id)
+		start := component.NewComponents()
+		start.Add(c)
+		return start
+	}
+
+	tests := []struct {
+		caption      string
+		complementer Complementer
+		start        component.ComponentID
+		ids          []component.ComponentID
+	}{
+		{
+			caption:      "dependencies within a depth",
+			complementer: DependenciesComplementer{AllComponents: cs, Depth: 3},
+			start:        "a",
+			ids:          []component.ComponentID{"a", "b", "c", "d", "e"},
+		},
+		{
+			caption:      "reverse dependencies within a depth",
+			complementer: ReverseDependenciesComplementer{AllComponents: cs, Depth: 3},
+			start:        "d",
+			ids:          []component.ComponentID{"a", "b", "c", "d", "z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			// Dependencies are walked in random order, so the result must not depend on which path
+			// reaches a component first.
+			for i := 0; i < 20; i++ {
+				result, err := tt.complementer.Complement(start(tt.start))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ids := sortedIDs(result); !reflect.DeepEqual(ids, tt.ids) {
+					t.Fatalf("unexpected components; want: %v, got: %v", tt.ids, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestTraversal(t *testing.T) {
+	cs := newTestComponents()
+	library := LabelsFilter{
+		Labels: map[string]string{"kind": "library"},
+	}
+	entry := LabelsFilter{
+		Labels: map[string]string{"role": "entry"},
+	}
+	start, err := entry.Filter(cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		caption      string
+		complementer Complementer
+		ids          []component.ComponentID
+		indirect     map[component.ComponentID][]component.ComponentID
+	}{
+		{
+			caption: "stop at a library",
+			complementer: DependenciesComplementer{
+				AllComponents: cs,
+				Depth:         -1,
+				Traversal: Traversal{
+					Stop: library,
+				},
+			},
+			ids: []component.ComponentID{"a", "b"},
+		},
+		{
+			caption: "skip a library",
+			complementer: DependenciesComplementer{
+				AllComponents: cs,
+				Depth:         1,
+				Traversal: Traversal{
+					Skip: library,
+				},
+			},
+			ids: []component.ComponentID{"a", "c"},
+		},
+		{
+			caption: "skip a library and keep transitive edges",
+			complementer: DependenciesComplementer{
+				AllComponents: cs,
+				Depth:         -1,
+				Traversal: Traversal{
+					Skip:                library,
+					KeepTransitiveEdges: true,
+				},
+			},
+			ids: []component.ComponentID{"a", "c", "d"},
+			indirect: map[component.ComponentID][]component.ComponentID{
+				"a": {"c"},
+			},
+		},
+		{
+			caption: "skip a library in reverse and keep transitive edges",
+			complementer: ReverseDependenciesComplementer{
+				AllComponents: cs,
+				Depth:         -1,
+				Traversal: Traversal{
+					Skip:                library,
+					KeepTransitiveEdges: true,
+				},
+			},
+			ids: []component.ComponentID{"a", "y"},
+			indirect: map[component.ComponentID][]component.ComponentID{
+				"y": {"a"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			result, err := tt.complementer.Complement(start)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := sortedIDs(result); !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("unexpected components; want: %v, got: %v", tt.ids, ids)
+			}
+			for id, deps := range tt.indirect {
+				c, _ := result.Get(id)
+				for _, d := range deps {
+					rel, ok := c.Dependencies[d]
+					if !ok || !rel.Indirect {
+						t.Errorf("`%v` must depend on `%v` indirectly; got: %v", id, d, c.Dependencies)
+					}
+				}
+				for depID := range c.Dependencies {
+					if depID == "b" || depID == "x" {
+						t.Errorf("a dependency on a skipped component must be removed; got: %v", c.Dependencies)
+					}
+				}
+			}
+			orig, _ := cs.Get("a")
+			if _, ok := orig.Dependencies["c"]; ok {
+				t.Error("the original component must not be modified")
+			}
+		})
+	}
+}
+
+func TestTransitiveReducer(t *testing.T) {
+	// a -> b -> c, a -> c, a -> d (reads), b -> d, c -> e (undefined)
+	// x -> y -> x, x -> z, y -> z
+	cs := newComponents([]testComponent{
+		{id: "a", deps: []component.ComponentID{"b", "c", "d"}},
+		{id: "b", deps: []component.ComponentID{"c", "d"}},
+		{id: "c", deps: []component.ComponentID{"e"}},
+		{id: "d"},
+		{id: "x", deps: []component.ComponentID{"y", "z"}},
+		{id: "y", deps: []component.ComponentID{"x", "z"}},
+		{id: "z"},
+	})
+	a, _ := cs.Get("a")
+	a.Dependencies["d"].Description = "reads"
+
+	result, err := TransitiveReducer{
+		PreservedRelations: []string{"reads"},
+	}.Transform(cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[component.ComponentID][]component.ComponentID{
+		"a": {"b", "d"},
+		"b": {"c", "d"},
+		"c": {"e"},
+		"d": {},
+		"x": {"y"},
+		"y": {"x", "z"},
+		"z": {},
+	}
+	for id, deps := range want {
+		c, _ := result.Get(id)
+		got := []component.ComponentID{}
+		for depID := range c.Dependencies {
+			got = append(got, depID)
+		}
+		sort.Slice(got, func(i, j int) bool {
+			return got[i] < got[j]
+		})
+		if !reflect.DeepEqual(got, deps) {
+			t.Errorf("unexpected dependencies of `%v`; want: %v, got: %v", id, deps, got)
+		}
+	}
+	if len(a.Dependencies) != 3 {
+		t.Error("the original component must not be modified")
+	}
+}
+
+func TestHiddenBypasser(t *testing.T) {
+	// a -> h1 -> h2 -> b, h1 -> a
+	cs := newComponents([]testComponent{
+		{id: "a", deps: []component.ComponentID{"h1"}},
+		{id: "h1", deps: []component.ComponentID{"h2", "a"}, hidden: true},
+		{id: "h2", deps: []component.ComponentID{"b"}, hidden: true},
+		{id: "b"},
+	})
+
+	result, err := HiddenBypasser{
+		AllComponents: cs,
+	}.Transform(cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids, want := sortedIDs(result), []component.ComponentID{"a", "b"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("unexpected components; want: %v, got: %v", want, ids)
+	}
+	a, _ := result.Get("a")
+	if len(a.Dependencies) != 1 {
+		t.Fatalf("`a` must depend only on `b`; got: %v", a.Dependencies)
+	}
+	if rel, ok := a.Dependencies["b"]; !ok || !rel.Indirect {
+		t.Errorf("`a` must depend on `b` indirectly; got: %v", a.Dependencies)
+	}
+}
+
+func TestFindPaths(t *testing.T) {
+	// a -> b -> d, a -> c -> d, a -> d, d -> a
+	cs := newComponents([]testComponent{
+		{id: "a", deps: []component.ComponentID{"b", "c", "d"}},
+		{id: "b", deps: []component.ComponentID{"d"}},
+		{id: "c", deps: []component.ComponentID{"d"}},
+		{id: "d", deps: []component.ComponentID{"a"}},
+	})
+
+	tests := []struct {
+		caption string
+		from    component.ComponentID
+		to      component.ComponentID
+		limit   int
+		paths   [][]component.ComponentID
+	}{
+		{
+			caption: "all paths in ascending order of their lengths",
+			from:    "a",
+			to:      "d",
+			limit:   -1,
+			paths: [][]component.ComponentID{
+				{"a", "d"},
+				{"a", "b", "d"},
+				{"a", "c", "d"},
+			},
+		},
+		{
+			caption: "paths are limited",
+			from:    "a",
+			to:      "d",
+			limit:   2,
+			paths: [][]component.ComponentID{
+				{"a", "d"},
+				{"a", "b", "d"},
+			},
+		},
+		{
+			caption: "a path through a cycle",
+			from:    "b",
+			to:      "c",
+			limit:   -1,
+			paths: [][]component.ComponentID{
+				{"b", "d", "a", "c"},
+			},
+		},
+		{
+			caption: "an undefined component",
+			from:    "a",
+			to:      "x",
+			limit:   -1,
+			paths:   [][]component.ComponentID{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			paths := FindPaths(cs, tt.from, tt.to, tt.limit)
+			if !reflect.DeepEqual(paths, tt.paths) {
+				t.Errorf("unexpected paths; want: %v, got: %v", tt.paths, paths)
+			}
+		})
+	}
+}
+
+func TestSpec_Group(t *testing.T) {
+	cs := newComponents([]testComponent{
+		{id: "a", labels: map[string]string{"role": "entry"}, deps: []component.ComponentID{"b"}},
+		{id: "b", deps: []component.ComponentID{"c"}},
+		{id: "c"},
+		{id: "h", hidden: true},
+	})
+
+	tests := []struct {
+		caption  string
+		spec     Spec
+		expected []component.ComponentID
+	}{
+		{
+			caption:  "a spec taking the defaults keeps all components including hidden ones",
+			spec:     Spec{},
+			expected: []component.ComponentID{"a", "b", "c", "h"},
+		},
+		{
+			caption:  "a spec taking the defaults applies transformers",
+			spec:     Spec{BypassHidden: true},
+			expected: []component.ComponentID{"a", "b", "c"},
+		},
+		{
+			caption: "a spec selecting components",
+			spec: Spec{
+				Filter:          "role=entry",
+				Complementation: "dep=1",
+			},
+			expected: []component.ComponentID{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			group, err := tt.spec.Group(cs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := sortedIDs(group); !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("unexpected components; want: %v, got: %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestFindPaths_denseGraph(t *testing.T) {
+	// 12 layers of 6 components where every component depends on all components in the next layer
+	// have 6^10 paths from the top to the bottom, which are too many to enumerate.
+	const layers = 12
+	const width = 6
+	tcs := []testComponent{
+		{id: "top", deps: layerIDs(1, width)},
+		{id: "bottom"},
+	}
+	for l := 1; l < layers-1; l++ {
+		for _, id := range layerIDs(l, width) {
+			deps := layerIDs(l+1, width)
+			if l == layers-2 {
+				deps = []component.ComponentID{"bottom"}
+			}
+			tcs = append(tcs, testComponent{id: id, deps: deps})
+		}
+	}
+	cs := newComponents(tcs)
+
+	for _, limit := range []int{5, -1} {
+		paths := FindPaths(cs, "top", "bottom", limit)
+		expected := limit
+		if limit < 0 {
+			expected = DefaultPathLimit
+		}
+		if len(paths) != expected {
+			t.Fatalf("unexpected number of paths; want: %v, got: %v", expected, len(paths))
+		}
+		for _, p := range paths {
+			if len(p) != layers || p[0] != "top" || p[len(p)-1] != "bottom" {
+				t.Fatalf("unexpected path; got: %v", p)
+			}
+		}
+	}
+}
+
+func layerIDs(layer int, width int) []component.ComponentID {
+	ids := []component.ComponentID{}
+	for i := 0; i < width; i++ {
+		ids = append(ids, component.ComponentID(fmt.Sprintf("l%02d-%d", layer, i)))
+	}
+	return ids
+}
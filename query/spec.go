@@ -0,0 +1,118 @@
+package query
+
+import (
+	"github.com/nihei9/felipe/component"
+)
+
+// Spec describes a query in the textual form used by command-line flags and definitions.
+// Empty fields take their defaults: all visible components are selected and all of their
+// dependencies are complemented.
+type Spec struct {
+	Filter          string
+	Complementation string
+	Stop            string
+	Skip            string
+	KeepTransitive  bool
+
+	Reduce       bool
+	Preserve     []string
+	BypassHidden bool
+}
+
+// Selects reports whether the spec selects a part of components rather than taking the defaults.
+func (s Spec) Selects() bool {
+	return s.Filter != "" || s.Complementation != "" || s.Stop != "" || s.Skip != ""
+}
+
+// Query makes a query on components.
+func (s Spec) Query(cs *component.Components) (Query, error) {
+	var err error
+
+	var filter Filter
+	if s.Filter != "" {
+		filter, err = ParseFilter(s.Filter)
+		if err != nil {
+			return Query{}, err
+		}
+	} else {
+		filter = AllPassFilter{}
+	}
+
+	traversal := Traversal{
+		KeepTransitiveEdges: s.KeepTransitive,
+	}
+	if s.Stop != "" {
+		traversal.Stop, err = ParseFilter(s.Stop)
+		if err != nil {
+			return Query{}, err
+		}
+	}
+	if s.Skip != "" {
+		traversal.Skip, err = ParseFilter(s.Skip)
+		if err != nil {
+			return Query{}, err
+		}
+	}
+
+	var complementer Complementer
+	if s.Complementation != "" {
+		complementer, err = ParseComplementation(s.Complementation, cs, traversal)
+		if err != nil {
+			return Query{}, err
+		}
+	} else {
+		complementer = DependenciesComplementer{
+			AllComponents: cs,
+			Depth:         -1,
+			Traversal:     traversal,
+		}
+	}
+
+	return Query{
+		Components:   cs,
+		Filter:       filter,
+		Complementer: complementer,
+		Transformers: s.Transformers(cs),
+	}, nil
+}
+
+// Transformers makes transformers applied to the result of the query.
+func (s Spec) Transformers(cs *component.Components) []Transformer {
+	transformers := []Transformer{}
+	if s.BypassHidden {
+		transformers = append(transformers, HiddenBypasser{
+			AllComponents: cs,
+		})
+	}
+	if s.Reduce {
+		transformers = append(transformers, TransitiveReducer{
+			PreservedRelations: s.Preserve,
+		})
+	}
+
+	return transformers
+}
+
+// Group returns components a diagram draws. It is the result of the query when the spec selects
+// components; otherwise, it is all components transformed by the transformers.
+func (s Spec) Group(cs *component.Components) (*component.Components, error) {
+	if s.Selects() {
+		q, err := s.Query(cs)
+		if err != nil {
+			return nil, err
+		}
+
+		return q.Do()
+	}
+
+	group := cs
+	for _, t := range s.Transformers(cs) {
+		var err error
+		group, err = t.Transform(group)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return group, nil
+}
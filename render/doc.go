@@ -0,0 +1,6 @@
+// Package render generates diagrams of components.
+//
+// A Renderer writes a diagram in a format: DOT writes the DOT language for Graphviz, and SVG and PNG
+// draw images with the built-in layout of the layout package. Components are styled by faces, whose
+// attributes are resolved by face.Engine and follow the attributes of Graphviz.
+package render
@@ -0,0 +1,209 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/awalterschulze/gographviz"
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+)
+
+const (
+	legendGraphName        = "cluster_legend"
+	clusterGraphNamePrefix = "cluster_group_"
+)
+
+var (
+	defaultGraphAttributes = map[string]string{
+		"rankdir":  "LR",
+		"fontsize": "11.0",
+	}
+	defaultNodeAttributes = map[string]string{
+		"penwidth": "0.75",
+	}
+	defaultEdgeAttributes = map[string]string{
+		"arrowsize": "0.75",
+		"penwidth":  "0.75",
+	}
+)
+
+// WriteDOT writes components in DOT. graph can be nil, in which case default graph attributes are used.
+func WriteDOT(w io.Writer, group *component.Components, cs *component.Components, fs []*face.Face, graph *face.Graph) error {
+	dot, err := GenerateDOT(group, cs, fs, graph)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(w, dot)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GenerateDOT(group *component.Components, cs *component.Components, fs []*face.Face, graph *face.Graph) (string, error) {
+	if graph == nil {
+		graph = &face.Graph{}
+	}
+
+	ast, _ := gographviz.ParseString("digraph G {}")
+	g := gographviz.NewGraph()
+	err := gographviz.Analyse(ast, g)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range mergeAttributes(defaultGraphAttributes, graph.Attributes) {
+		err := g.AddAttr("G", k, v)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	faces := face.Engine{
+		Faces:      fs,
+		Components: cs,
+	}
+	nodeAttrs := func(c *component.Component) (map[string]string, error) {
+		attrs, err := faces.Resolve(c)
+		if err != nil {
+			return nil, err
+		}
+		return mergeAttributes(defaultNodeAttributes, graph.Node, attrs), nil
+	}
+
+	for _, id := range group.GetIDs() {
+		c, _ := group.Get(id)
+		nAttrs, err := nodeAttrs(c)
+		if err != nil {
+			return "", err
+		}
+		err = addNode(g, graph, c, nAttrs)
+		if err != nil {
+			return "", err
+		}
+		for dcid, rel := range c.Dependencies {
+			d, ok := neighbour(group, cs, dcid)
+			if !ok {
+				continue
+			}
+			nAttrs, err := nodeAttrs(d)
+			if err != nil {
+				return "", err
+			}
+			err = addNode(g, graph, d, nAttrs)
+			if err != nil {
+				return "", err
+			}
+
+			eAttrs := mergeAttributes(defaultEdgeAttributes, graph.Edge, map[string]string{
+				"label": fmt.Sprintf("\"%s\"", rel.Description),
+			})
+			if rel.Indirect {
+				eAttrs["style"] = "dashed"
+			}
+			err = g.AddEdge(fmt.Sprintf("\"%s\"", c.ID.String()), fmt.Sprintf("\"%s\"", d.ID.String()), true, eAttrs)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if graph.Legend != "" && len(fs) > 0 {
+		err := addLegend(g, fs, graph)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return g.String(), nil
+}
+
+// addNode adds a node of a component to the graph, or to the cluster of its group when the graph groups components.
+func addNode(g *gographviz.Graph, graph *face.Graph, c *component.Component, attrs map[string]string) error {
+	parent := "G"
+	if v, ok := c.Labels[graph.GroupBy]; ok && graph.GroupBy != "" {
+		parent = fmt.Sprintf("\"%s%s\"", clusterGraphNamePrefix, strings.Replace(v, "\"", "\\\"", -1))
+		if !g.IsSubGraph(parent) {
+			err := g.AddSubGraph("G", parent, map[string]string{
+				"label": quote(v),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return g.AddNode(parent, fmt.Sprintf("\"%s\"", c.ID.String()), attrs)
+}
+
+// addLegend adds a subgraph that has a node per face. Each node is styled by the face and labeled
+// with the name and the selector of the face.
+func addLegend(g *gographviz.Graph, fs []*face.Face, graph *face.Graph) error {
+	err := g.AddSubGraph("G", legendGraphName, map[string]string{
+		"label": quote(graph.Legend),
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, f := range fs {
+		attrs := mergeAttributes(defaultNodeAttributes, graph.Node)
+		for k, v := range f.Attributes {
+			// Templates of these attributes refer to a component, so the legend cannot express them.
+			if k == "label" || k == "xlabel" || k == "tooltip" {
+				continue
+			}
+			attrs[k] = v
+		}
+		attrs["label"] = quote(fmt.Sprintf("%s\n%s", f.Name, selector(f)))
+
+		err := g.AddNode(legendGraphName, fmt.Sprintf("\"legend_%d\"", i), attrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func selector(f *face.Face) string {
+	if f.Filter == nil {
+		return "all components"
+	}
+	if s, ok := f.Filter.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return ""
+}
+
+// neighbour returns a component that a member of a group depends on. A member of the group takes precedence
+// over the one in cs, which can differ from it when the group is transformed, so that faces style a component
+// in the same way whether it is drawn as a member or as a neighbour.
+func neighbour(group *component.Components, cs *component.Components, id component.ComponentID) (*component.Component, bool) {
+	if c, ok := group.Get(id); ok {
+		return c, true
+	}
+
+	return cs.Get(id)
+}
+
+// mergeAttributes merges attributes into a new map. Latter attributes override former ones.
+func mergeAttributes(attrs ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, a := range attrs {
+		for k, v := range a {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
+
+func quote(s string) string {
+	s = strings.Replace(s, "\"", "\\\"", -1)
+	s = strings.Replace(s, "\n", "\\n", -1)
+	return fmt.Sprintf("\"%s\"", s)
+}
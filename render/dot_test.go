@@ -0,0 +1,157 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/query"
+)
+
+func TestGenerateDOT(t *testing.T) {
+	cs := component.NewComponents()
+	a := component.NewComponent(component.NilComponentID, "a")
+	a.DependOn("b", &component.Relation{})
+	cs.Add(a)
+	b := component.NewComponent(component.NilComponentID, "b")
+	b.AddLabel("tier", "db")
+	cs.Add(b)
+
+	fs := []*face.Face{
+		{
+			Name: "database",
+			Filter: query.LabelsFilter{
+				Labels: map[string]string{"tier": "db"},
+			},
+			Attributes: map[string]string{
+				"shape":    "cylinder",
+				"label":    `"{{.ID}}"`,
+				"penwidth": "2.0",
+			},
+		},
+	}
+
+	tests := []struct {
+		caption  string
+		graph    *face.Graph
+		contains []string
+		excludes []string
+	}{
+		{
+			caption: "default graph attributes",
+			contains: []string{
+				"rankdir=LR;",
+				"fontsize=11.0;",
+				`"a" [ penwidth=0.75 ];`,
+				`"b" [ label="b", penwidth=2.0, shape=cylinder ];`,
+			},
+			excludes: []string{
+				legendGraphName,
+			},
+		},
+		{
+			caption: "graph attributes, default node and edge attributes and a legend",
+			graph: &face.Graph{
+				Attributes: map[string]string{"rankdir": "TB"},
+				Node:       map[string]string{"shape": "box"},
+				Edge:       map[string]string{"color": "gray"},
+				Legend:     "Styles",
+			},
+			contains: []string{
+				"rankdir=TB;",
+				`"a" [ penwidth=0.75, shape=box ];`,
+				`"b" [ label="b", penwidth=2.0, shape=cylinder ];`,
+				`"a"->"b"[ arrowsize=0.75, color=gray, label="", penwidth=0.75 ];`,
+				"subgraph cluster_legend {",
+				`label="Styles";`,
+				`"legend_0" [ label="database\ntier=db", penwidth=2.0, shape=cylinder ];`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			dot, err := GenerateDOT(cs, cs, fs, tt.graph)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(dot, s) {
+					t.Errorf("DOT must contain `%s`; got:\n%s", s, dot)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(dot, s) {
+					t.Errorf("DOT must not contain `%s`; got:\n%s", s, dot)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateDOT_transformedGroup(t *testing.T) {
+	cs := component.NewComponents()
+	a := component.NewComponent(component.NilComponentID, "a")
+	a.DependOn("b", &component.Relation{})
+	cs.Add(a)
+	b := component.NewComponent(component.NilComponentID, "b")
+	b.DependOn("c", &component.Relation{})
+	cs.Add(b)
+	cs.Add(component.NewComponent(component.NilComponentID, "c"))
+
+	// The group has its own `b` that no longer depends on `c`, as transformers make.
+	group := component.NewComponents()
+	group.Add(component.NewComponent(component.NilComponentID, "b"))
+	group.Add(a)
+
+	fs := []*face.Face{
+		{
+			Name: "dependencies",
+			Attributes: map[string]string{
+				"label": `"{{.Dependencies}}"`,
+			},
+		},
+	}
+
+	dot, err := GenerateDOT(group, cs, fs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dot, `"b" [ label="0", penwidth=0.75 ];`) {
+		t.Errorf("a member of the group must be styled by itself even when it is a neighbour; got:\n%s", dot)
+	}
+	if strings.Contains(dot, `"c"`) {
+		t.Errorf("dependencies of the original component must not be drawn; got:\n%s", dot)
+	}
+}
+
+func TestGenerateDOT_groupBy(t *testing.T) {
+	cs := component.NewComponents()
+	a := component.NewComponent(component.NilComponentID, "a")
+	a.AddLabel("team", "payments")
+	a.DependOn("b", &component.Relation{})
+	a.DependOn("c", &component.Relation{})
+	cs.Add(a)
+	b := component.NewComponent(component.NilComponentID, "b")
+	b.AddLabel("team", "payments")
+	cs.Add(b)
+	cs.Add(component.NewComponent(component.NilComponentID, "c"))
+
+	dot, err := GenerateDOT(cs, cs, nil, &face.Graph{
+		GroupBy: "team",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`subgraph "cluster_group_payments" {`,
+		`label="payments";`,
+	} {
+		if !strings.Contains(dot, s) {
+			t.Errorf("DOT must contain `%s`; got:\n%s", s, dot)
+		}
+	}
+	if strings.Count(dot, "subgraph") != 1 {
+		t.Errorf("components without the label must not be grouped; got:\n%s", dot)
+	}
+}
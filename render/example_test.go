@@ -0,0 +1,65 @@
+package render_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/nihei9/felipe/loader"
+	"github.com/nihei9/felipe/query"
+	"github.com/nihei9/felipe/render"
+)
+
+func Example() {
+	cs, err := loader.Read(strings.NewReader(`kind: components
+version: 1
+components:
+  - id: web
+    labels:
+      tier: front
+    dependencies:
+      - id: db
+  - id: db
+    labels:
+      tier: db
+`))
+	if err != nil {
+		panic(err)
+	}
+	faces, err := loader.ReadFaces(strings.NewReader(`kind: faces
+version: 1
+graph:
+  attributes:
+    rankdir: TB
+faces:
+  - name: database
+    targets:
+      match_labels:
+        tier: db
+    attributes:
+      shape: cylinder
+`))
+	if err != nil {
+		panic(err)
+	}
+
+	// Render the front tier together with the components it depends on.
+	group, err := query.LabelsFilter{
+		Labels: map[string]string{"tier": "front"},
+	}.Filter(cs)
+	if err != nil {
+		panic(err)
+	}
+	err = render.DOT.Render(os.Stdout, group, cs, faces.Faces, faces.Graph)
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// digraph G {
+	// 	fontsize=11.0;
+	// 	rankdir=TB;
+	// 	"web"->"db"[ arrowsize=0.75, label="", penwidth=0.75 ];
+	// 	"db" [ penwidth=0.75, shape=cylinder ];
+	// 	"web" [ penwidth=0.75 ];
+	//
+	// }
+}
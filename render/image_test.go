@@ -0,0 +1,138 @@
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/query"
+)
+
+func newImageTestComponents() *component.Components {
+	cs := component.NewComponents()
+	a := component.NewComponent(component.NilComponentID, "a")
+	a.DependOn("b", &component.Relation{Description: "reads"})
+	cs.Add(a)
+	b := component.NewComponent(component.NilComponentID, "b")
+	b.AddLabel("tier", "db")
+	cs.Add(b)
+
+	return cs
+}
+
+var imageTestFaces = []*face.Face{
+	{
+		Name: "database",
+		Filter: query.LabelsFilter{
+			Labels: map[string]string{"tier": "db"},
+		},
+		Attributes: map[string]string{
+			"shape":     "box",
+			"style":     "filled",
+			"fillcolor": "#123456",
+			"label":     `"{{.ID}}\n(db)"`,
+		},
+	},
+}
+
+func TestWriteSVG(t *testing.T) {
+	cs := newImageTestComponents()
+	var b bytes.Buffer
+	err := WriteSVG(&b, cs, cs, imageTestFaces, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svg := b.String()
+	for _, s := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		`<g class="node"><title>a</title>`,
+		`<ellipse `,
+		`fill="#123456"`,
+		`>b</text>`,
+		`>(db)</text>`,
+		`<g class="edge"><title>a-&gt;b</title>`,
+		`>reads</text>`,
+	} {
+		if !strings.Contains(svg, s) {
+			t.Errorf("SVG must contain `%s`; got:\n%s", s, svg)
+		}
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	cs := newImageTestComponents()
+	var b bytes.Buffer
+	err := WritePNG(&b, cs, cs, imageTestFaces, &face.Graph{
+		Attributes: map[string]string{"rankdir": "TB"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := newScene(cs, cs, imageTestFaces, &face.Graph{
+		Attributes: map[string]string{"rankdir": "TB"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.X < int(s.width) || size.Y < int(s.height) {
+		t.Errorf("unexpected size; want: %vx%v, got: %v", s.width, s.height, size)
+	}
+	for _, n := range s.nodes {
+		if n.id != "b" {
+			continue
+		}
+		// A corner inside the border is filled and has no text.
+		r, g, bl, _ := img.At(int(n.node.X-n.node.Width/2)+3, int(n.node.Y-n.node.Height/2)+3).RGBA()
+		if r>>8 != 0x12 || g>>8 != 0x34 || bl>>8 != 0x56 {
+			t.Errorf("a filled node must be painted in the fill color; got: %x %x %x", r>>8, g>>8, bl>>8)
+		}
+	}
+}
+
+func TestNewSceneStyle(t *testing.T) {
+	tests := []struct {
+		caption   string
+		attrs     map[string]string
+		color     string
+		fillColor string
+	}{
+		{
+			caption:   "a filled node uses light gray by default",
+			attrs:     map[string]string{"style": "filled"},
+			color:     black.Hex(),
+			fillColor: lightgray.Hex(),
+		},
+		{
+			caption:   "a filled node uses `color` without `fillcolor`",
+			attrs:     map[string]string{"style": "filled", "color": "red"},
+			color:     "#FF0000",
+			fillColor: "#FF0000",
+		},
+		{
+			caption:   "a color that cannot be parsed falls back to the default",
+			attrs:     map[string]string{"style": "filled", "color": "0.5 0.5 0.5"},
+			color:     black.Hex(),
+			fillColor: lightgray.Hex(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			s := newSceneStyle(tt.attrs)
+			if s.fillColor.Hex() != tt.fillColor {
+				t.Errorf("unexpected fill color; want: %v, got: %v", tt.fillColor, s.fillColor.Hex())
+			}
+			if s.color.Hex() != tt.color {
+				t.Errorf("unexpected color; want: %v, got: %v", tt.color, s.color.Hex())
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/internal/dotattr"
+	"github.com/nihei9/felipe/layout"
+)
+
+const (
+	dashLength = 5.0
+	gapLength  = 3.0
+)
+
+// WritePNG lays out components and writes them in PNG without Graphviz. Labels are drawn with
+// a built-in bitmap font that supports only printable ASCII characters.
+func WritePNG(w io.Writer, group *component.Components, cs *component.Components, fs []*face.Face, graph *face.Graph) error {
+	s, err := newScene(group, cs, fs, graph)
+	if err != nil {
+		return err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(s.width)), int(math.Ceil(s.height))))
+	c := &canvas{img: img}
+	c.fillPolygon([]layout.Point{{X: 0, Y: 0}, {X: s.width, Y: 0}, {X: s.width, Y: s.height}, {X: 0, Y: s.height}}, s.background)
+
+	for _, e := range s.edges {
+		if e.style.invisible {
+			continue
+		}
+		c.strokePolyline(e.edge.Points, false, e.style)
+		if len(e.arrow) > 0 {
+			c.fillPolygon(e.arrow, e.style.color)
+		}
+		if e.label != "" {
+			c.drawText([]string{e.label}, e.labelAt, e.style)
+		}
+	}
+
+	for _, n := range s.nodes {
+		if n.style.invisible {
+			continue
+		}
+		if n.shape != "none" {
+			outline := n.outline()
+			if n.style.filled {
+				c.fillPolygon(outline, n.style.fillColor)
+			}
+			c.strokePolyline(outline, true, n.style)
+			if n.shape == "cylinder" {
+				c.strokePolyline(cylinderTop(n), false, n.style)
+			}
+		}
+		c.drawText(n.lines, layout.Point{X: n.node.X, Y: n.node.Y}, n.style)
+	}
+
+	return png.Encode(w, img)
+}
+
+// cylinderTop returns the front arc of the top of a cylinder.
+func cylinderTop(n *sceneNode) []layout.Point {
+	const segments = 16
+	hw := n.node.Width / 2
+	ry := cylinderRadius(n.node.Height)
+	cy := n.node.Y - n.node.Height/2 + ry
+	ps := []layout.Point{}
+	for i := 0; i <= segments; i++ {
+		a := math.Pi * float64(i) / segments
+		ps = append(ps, layout.Point{X: n.node.X - hw*math.Cos(a), Y: cy + ry*math.Sin(a)})
+	}
+	return ps
+}
+
+// canvas draws shapes on an image without anti-aliasing.
+type canvas struct {
+	img *image.RGBA
+}
+
+func (c *canvas) set(x, y int, col dotattr.RGB) {
+	c.img.SetRGBA(x, y, color.RGBA{R: col.R, G: col.G, B: col.B, A: 0xFF})
+}
+
+// fillPolygon fills a polygon by the even-odd rule.
+func (c *canvas) fillPolygon(ps []layout.Point, col dotattr.RGB) {
+	if len(ps) < 3 {
+		return
+	}
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, p := range ps {
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+
+	b := c.img.Bounds()
+	for y := int(math.Floor(minY)); y <= int(math.Ceil(maxY)); y++ {
+		if y < b.Min.Y || y >= b.Max.Y {
+			continue
+		}
+		sy := float64(y) + 0.5
+		xs := []float64{}
+		for i := range ps {
+			p, q := ps[i], ps[(i+1)%len(ps)]
+			if (p.Y <= sy && q.Y > sy) || (q.Y <= sy && p.Y > sy) {
+				xs = append(xs, p.X+(sy-p.Y)*(q.X-p.X)/(q.Y-p.Y))
+			}
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			for x := int(math.Ceil(xs[i] - 0.5)); float64(x)+0.5 <= xs[i+1]; x++ {
+				c.set(x, y, col)
+			}
+		}
+	}
+}
+
+// strokePolyline draws lines by stamping a square of the pen width along them.
+func (c *canvas) strokePolyline(ps []layout.Point, closed bool, style *sceneStyle) {
+	if len(ps) < 2 {
+		return
+	}
+	if closed {
+		ps = append(append([]layout.Point{}, ps...), ps[0])
+	}
+
+	r := math.Max(style.penWidth, 1) / 2
+	walked := 0.0
+	for i := 0; i+1 < len(ps); i++ {
+		p, q := ps[i], ps[i+1]
+		d := math.Hypot(q.X-p.X, q.Y-p.Y)
+		for t := 0.0; t <= d; t += 0.5 {
+			if style.dashed && math.Mod(walked+t, dashLength+gapLength) >= dashLength {
+				continue
+			}
+			x := p.X + (q.X-p.X)*t/math.Max(d, 1e-9)
+			y := p.Y + (q.Y-p.Y)*t/math.Max(d, 1e-9)
+			for py := int(math.Floor(y - r)); float64(py) < y+r; py++ {
+				for px := int(math.Floor(x - r)); float64(px) < x+r; px++ {
+					c.set(px, py, style.color)
+				}
+			}
+		}
+		walked += d
+	}
+}
+
+// drawText draws lines of text centered on a point. The glyphs are scaled by the font size.
+func (c *canvas) drawText(lines []string, center layout.Point, style *sceneStyle) {
+	scale := int(math.Max(1, math.Floor(style.fontSize/12+0.5)))
+	lineHeight := style.fontSize * lineHeightRatio
+	top := center.Y - lineHeight*float64(len(lines)-1)/2
+	for i, l := range lines {
+		rs := []rune(l)
+		width := len(rs)*glyphAdvance*scale - scale
+		x0 := int(math.Floor(center.X)) - width/2
+		y0 := int(math.Floor(top+lineHeight*float64(i))) - glyphHeight*scale/2
+		for j, r := range rs {
+			g := glyph(r)
+			for col := 0; col < glyphWidth; col++ {
+				for row := 0; row < glyphHeight; row++ {
+					if g[col]&(1<<uint(row)) == 0 {
+						continue
+					}
+					x := x0 + (j*glyphAdvance+col)*scale
+					y := y0 + row*scale
+					for dy := 0; dy < scale; dy++ {
+						for dx := 0; dx < scale; dx++ {
+							c.set(x+dx, y+dy, style.fontColor)
+						}
+					}
+				}
+			}
+		}
+	}
+}
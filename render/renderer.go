@@ -0,0 +1,92 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+)
+
+// Renderer writes a diagram of a group of components. cs holds all components including the group, and
+// components in it that the group depends on or is depended on by are drawn as neighbours of the group.
+// Faces style the components, and graph, which can be nil, styles the whole diagram.
+type Renderer interface {
+	Render(w io.Writer, group *component.Components, cs *component.Components, fs []*face.Face, graph *face.Graph) error
+}
+
+// RendererFunc adapts a function to a Renderer.
+type RendererFunc func(w io.Writer, group *component.Components, cs *component.Components, fs []*face.Face, graph *face.Graph) error
+
+func (f RendererFunc) Render(w io.Writer, group *component.Components, cs *component.Components, fs []*face.Face, graph *face.Graph) error {
+	return f(w, group, cs, fs, graph)
+}
+
+var (
+	// DOT renders in the DOT language of Graphviz.
+	DOT Renderer = RendererFunc(WriteDOT)
+
+	// SVG and PNG render images with the built-in layout and do not need Graphviz.
+	SVG Renderer = RendererFunc(WriteSVG)
+	PNG Renderer = RendererFunc(WritePNG)
+)
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]Renderer{}
+)
+
+func init() {
+	Register("dot", DOT)
+	Register("svg", SVG)
+	Register("png", PNG)
+}
+
+// Register makes a renderer available by a format name, such as `felipe render --format <name>`.
+// It panics when the name is already registered or the renderer is nil, like database/sql.Register.
+func Register(name string, r Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+
+	if r == nil {
+		panic("render: Register renderer is nil")
+	}
+	if _, dup := renderers[name]; dup {
+		panic(fmt.Sprintf("render: Register called twice for format `%s`", name))
+	}
+	renderers[name] = r
+}
+
+// Lookup returns a renderer registered by a format name.
+func Lookup(name string) (Renderer, error) {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+
+	r, ok := renderers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown format `%s`; it must be one of %s", name, strings.Join(formats(), ", "))
+	}
+
+	return r, nil
+}
+
+// Formats returns names of registered formats in sorted order.
+func Formats() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+
+	return formats()
+}
+
+func formats() []string {
+	names := []string{}
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,62 @@
+package render
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+)
+
+func TestRegister(t *testing.T) {
+	Register("test-ids", RendererFunc(func(w io.Writer, group *component.Components, cs *component.Components, fs []*face.Face, graph *face.Graph) error {
+		for _, id := range group.GetIDs() {
+			_, err := io.WriteString(w, id.String()+"\n")
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	defer func() {
+		renderersMu.Lock()
+		delete(renderers, "test-ids")
+		renderersMu.Unlock()
+	}()
+
+	if !reflect.DeepEqual(Formats(), []string{"dot", "png", "svg", "test-ids"}) {
+		t.Errorf("unexpected formats; got: %v", Formats())
+	}
+
+	r, err := Lookup("test-ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := component.NewComponents()
+	cs.Add(component.NewComponent(component.NilComponentID, "a"))
+	var b bytes.Buffer
+	err = r.Render(&b, cs, cs, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "a\n" {
+		t.Errorf("unexpected output; got: %v", b.String())
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("registering a format twice must panic")
+			}
+		}()
+		Register("dot", DOT)
+	}()
+
+	_, err = Lookup("unknown")
+	if err == nil || !strings.Contains(err.Error(), "dot, png, svg") {
+		t.Errorf("an error must list registered formats; got: %v", err)
+	}
+}
@@ -0,0 +1,422 @@
+package render
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/internal/dotattr"
+	"github.com/nihei9/felipe/layout"
+)
+
+const (
+	pointsPerInch   = 72.0
+	defaultFontSize = 14.0
+	charWidthRatio  = 0.6
+	lineHeightRatio = 1.2
+	minNodeWidth    = 0.75 * pointsPerInch
+	minNodeHeight   = 0.5 * pointsPerInch
+	arrowLength     = 10.0
+)
+
+var (
+	black     = dotattr.RGB{R: 0x00, G: 0x00, B: 0x00}
+	white     = dotattr.RGB{R: 0xFF, G: 0xFF, B: 0xFF}
+	lightgray = dotattr.RGB{R: 0xD3, G: 0xD3, B: 0xD3}
+)
+
+// scene is a laid out graph whose visual attributes are resolved from faces. Image renderers draw it.
+type scene struct {
+	width      float64
+	height     float64
+	background dotattr.RGB
+	nodes      []*sceneNode
+	edges      []*sceneEdge
+}
+
+type sceneNode struct {
+	id      string
+	tooltip string
+	node    *layout.Node
+	shape   string
+	lines   []string
+	style   *sceneStyle
+}
+
+type sceneEdge struct {
+	edge  *layout.Edge
+	label string
+
+	// arrow is a triangle of the arrowhead. It is empty when the edge has no arrowhead.
+	arrow   []layout.Point
+	labelAt layout.Point
+	style   *sceneStyle
+}
+
+type sceneStyle struct {
+	color     dotattr.RGB
+	fillColor dotattr.RGB
+	fontColor dotattr.RGB
+	fontSize  float64
+	penWidth  float64
+	filled    bool
+	dashed    bool
+	rounded   bool
+	invisible bool
+}
+
+func newScene(group *component.Components, cs *component.Components, fs []*face.Face, graph *face.Graph) (*scene, error) {
+	if graph == nil {
+		graph = &face.Graph{}
+	}
+	gAttrs := mergeAttributes(defaultGraphAttributes, graph.Attributes)
+	faces := face.Engine{
+		Faces:      fs,
+		Components: cs,
+	}
+
+	s := &scene{
+		background: white,
+	}
+	if bg, ok := dotattr.ParseColor(gAttrs["bgcolor"]); ok {
+		s.background = bg
+	}
+
+	g := &layout.Graph{}
+	nodes := map[component.ComponentID]*sceneNode{}
+	addNode := func(c *component.Component) error {
+		if _, ok := nodes[c.ID]; ok {
+			return nil
+		}
+		attrs, err := faces.Resolve(c)
+		if err != nil {
+			return err
+		}
+		n := newSceneNode(c, mergeAttributes(defaultNodeAttributes, graph.Node, attrs))
+		nodes[c.ID] = n
+		s.nodes = append(s.nodes, n)
+		g.Nodes = append(g.Nodes, n.node)
+		return nil
+	}
+
+	hasLabel := false
+	for _, id := range group.GetIDs() {
+		c, _ := group.Get(id)
+		err := addNode(c)
+		if err != nil {
+			return nil, err
+		}
+		depIDs := []component.ComponentID{}
+		for dcid := range c.Dependencies {
+			depIDs = append(depIDs, dcid)
+		}
+		sort.Slice(depIDs, func(i, j int) bool {
+			return depIDs[i] < depIDs[j]
+		})
+		for _, dcid := range depIDs {
+			d, ok := neighbour(group, cs, dcid)
+			if !ok {
+				continue
+			}
+			err := addNode(d)
+			if err != nil {
+				return nil, err
+			}
+
+			rel := c.Dependencies[dcid]
+			attrs := mergeAttributes(defaultEdgeAttributes, graph.Edge)
+			if rel.Indirect {
+				attrs["style"] = "dashed"
+			}
+			e := &sceneEdge{
+				edge: &layout.Edge{
+					From: c.ID.String(),
+					To:   d.ID.String(),
+				},
+				label: rel.Description,
+				style: newSceneStyle(attrs),
+			}
+			if rel.Description != "" {
+				hasLabel = true
+			}
+			if strings.Contains(attrs["arrowhead"], "none") || strings.Contains(attrs["dir"], "none") {
+				e.arrow = []layout.Point{}
+			}
+			s.edges = append(s.edges, e)
+			g.Edges = append(g.Edges, e.edge)
+		}
+	}
+
+	opts := layout.Options{
+		RankSep: 0.5 * pointsPerInch,
+		NodeSep: 0.25 * pointsPerInch,
+		Margin:  8,
+	}
+	if sep, ok := parseFloat(gAttrs["ranksep"]); ok {
+		opts.RankSep = sep * pointsPerInch
+	}
+	if sep, ok := parseFloat(gAttrs["nodesep"]); ok {
+		opts.NodeSep = sep * pointsPerInch
+	}
+	if hasLabel {
+		// Make room for edge labels between ranks.
+		opts.RankSep += defaultFontSize * lineHeightRatio
+	}
+	switch strings.ToUpper(dotattr.Unquote(gAttrs["rankdir"])) {
+	case "LR", "RL":
+		opts.Direction = layout.LeftToRight
+	}
+	err := layout.Layout(g, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.width, s.height = g.Width, g.Height
+
+	for _, e := range s.edges {
+		e.placeArrow()
+		e.labelAt = midpoint(e.edge.Points)
+	}
+
+	return s, nil
+}
+
+func newSceneNode(c *component.Component, attrs map[string]string) *sceneNode {
+	style := newSceneStyle(attrs)
+	n := &sceneNode{
+		id:      c.ID.String(),
+		tooltip: c.ID.String(),
+		shape:   normalizeShape(dotattr.Unquote(attrs["shape"])),
+		lines:   labelLines(c.ID.String(), attrs["label"]),
+		style:   style,
+	}
+	if tooltip, ok := attrs["tooltip"]; ok {
+		n.tooltip = dotattr.Unquote(tooltip)
+	}
+
+	textWidth := 0.0
+	for _, l := range n.lines {
+		textWidth = math.Max(textWidth, textWidthOf(l, style.fontSize))
+	}
+	textHeight := float64(len(n.lines)) * style.fontSize * lineHeightRatio
+
+	var w, h float64
+	switch n.shape {
+	case "ellipse":
+		w, h = textWidth*math.Sqrt2+8, textHeight*math.Sqrt2
+	case "circle":
+		w = math.Max(textWidth*math.Sqrt2+8, textHeight*math.Sqrt2)
+		w = math.Max(w, minNodeHeight)
+		h = w
+	case "diamond":
+		w, h = textWidth*2+16, textHeight*2
+	case "hexagon", "octagon":
+		w, h = textWidth+32, textHeight+8
+	case "cylinder":
+		w, h = textWidth+16, textHeight+20
+	case "none":
+		w, h = textWidth+8, textHeight+4
+	default:
+		w, h = textWidth+16, textHeight+8
+	}
+	if n.shape != "none" {
+		w, h = math.Max(w, minNodeWidth), math.Max(h, minNodeHeight)
+	}
+	// Graphviz treats `width` and `height` as minimum sizes in inches.
+	if v, ok := parseFloat(attrs["width"]); ok {
+		w = math.Max(w, v*pointsPerInch)
+	}
+	if v, ok := parseFloat(attrs["height"]); ok {
+		h = math.Max(h, v*pointsPerInch)
+	}
+	if n.shape == "circle" {
+		w = math.Max(w, h)
+		h = w
+	}
+	n.node = &layout.Node{
+		ID:     n.id,
+		Width:  w,
+		Height: h,
+	}
+
+	return n
+}
+
+func newSceneStyle(attrs map[string]string) *sceneStyle {
+	s := &sceneStyle{
+		color:     black,
+		fontColor: black,
+		fontSize:  defaultFontSize,
+		penWidth:  1,
+	}
+	for _, st := range strings.Split(dotattr.Unquote(attrs["style"]), ",") {
+		switch strings.TrimSpace(st) {
+		case "filled":
+			s.filled = true
+		case "dashed", "dotted":
+			s.dashed = true
+		case "rounded":
+			s.rounded = true
+		case "bold":
+			s.penWidth = 2
+		case "invis":
+			s.invisible = true
+		}
+	}
+	// Filled nodes use `fillcolor`, then `color`, then light gray like Graphviz. Colors that cannot be
+	// parsed are ignored, so that they fall back to the defaults.
+	s.fillColor = lightgray
+	if c, ok := dotattr.ParseColor(attrs["color"]); ok {
+		s.color = c
+		s.fillColor = c
+	}
+	if c, ok := dotattr.ParseColor(attrs["fillcolor"]); ok {
+		s.fillColor = c
+	}
+	if c, ok := dotattr.ParseColor(attrs["fontcolor"]); ok {
+		s.fontColor = c
+	}
+	if v, ok := parseFloat(attrs["fontsize"]); ok && v > 0 {
+		s.fontSize = v
+	}
+	if v, ok := parseFloat(attrs["penwidth"]); ok {
+		s.penWidth = v
+	}
+
+	return s
+}
+
+// normalizeShape maps DOT shapes to shapes image renderers can draw. Unsupported shapes become boxes.
+func normalizeShape(shape string) string {
+	switch strings.ToLower(shape) {
+	case "", "ellipse", "oval":
+		return "ellipse"
+	case "circle", "doublecircle", "point":
+		return "circle"
+	case "diamond":
+		return "diamond"
+	case "hexagon":
+		return "hexagon"
+	case "octagon":
+		return "octagon"
+	case "cylinder":
+		return "cylinder"
+	case "none", "plaintext", "plain":
+		return "none"
+	default:
+		return "box"
+	}
+}
+
+// labelLines splits a DOT label into lines. HTML-like labels cannot be drawn, so the ID is used instead.
+func labelLines(id string, label string) []string {
+	label = strings.TrimSpace(label)
+	if label == "" || strings.HasPrefix(label, "<") {
+		return []string{id}
+	}
+	label = dotattr.Unquote(label)
+	r := strings.NewReplacer(`\N`, id, `\n`, "\n", `\l`, "\n", `\r`, "\n", `\"`, `"`)
+	label = strings.TrimRight(r.Replace(label), "\n")
+
+	return strings.Split(label, "\n")
+}
+
+func textWidthOf(s string, fontSize float64) float64 {
+	return float64(len([]rune(s))) * fontSize * charWidthRatio
+}
+
+func parseFloat(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(dotattr.Unquote(s), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// placeArrow shortens the last segment of the edge by the arrowhead and sets the arrowhead triangle.
+func (e *sceneEdge) placeArrow() {
+	ps := e.edge.Points
+	if e.arrow != nil || len(ps) < 2 {
+		return
+	}
+	tip, prev := ps[len(ps)-1], ps[len(ps)-2]
+	dx, dy := tip.X-prev.X, tip.Y-prev.Y
+	d := math.Hypot(dx, dy)
+	if d == 0 {
+		return
+	}
+	dx, dy = dx/d, dy/d
+	l := arrowLength * math.Max(e.style.penWidth, 1)
+	if l > d {
+		l = d
+	}
+	base := layout.Point{X: tip.X - dx*l, Y: tip.Y - dy*l}
+	e.arrow = []layout.Point{
+		tip,
+		{X: base.X - dy*l/3, Y: base.Y + dx*l/3},
+		{X: base.X + dy*l/3, Y: base.Y - dx*l/3},
+	}
+	ps[len(ps)-1] = base
+}
+
+func midpoint(ps []layout.Point) layout.Point {
+	if len(ps) == 0 {
+		return layout.Point{}
+	}
+	if len(ps)%2 == 1 {
+		return ps[len(ps)/2]
+	}
+	a, b := ps[len(ps)/2-1], ps[len(ps)/2]
+	return layout.Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+}
+
+// outline returns a polygon approximating the shape of a node.
+func (n *sceneNode) outline() []layout.Point {
+	x, y := n.node.X, n.node.Y
+	w, h := n.node.Width/2, n.node.Height/2
+	switch n.shape {
+	case "ellipse", "circle":
+		const segments = 48
+		ps := make([]layout.Point, 0, segments)
+		for i := 0; i < segments; i++ {
+			a := 2 * math.Pi * float64(i) / segments
+			ps = append(ps, layout.Point{X: x + w*math.Cos(a), Y: y + h*math.Sin(a)})
+		}
+		return ps
+	case "diamond":
+		return []layout.Point{{X: x, Y: y - h}, {X: x + w, Y: y}, {X: x, Y: y + h}, {X: x - w, Y: y}}
+	case "hexagon":
+		i := math.Min(w/2, 12)
+		return []layout.Point{
+			{X: x - w + i, Y: y - h}, {X: x + w - i, Y: y - h}, {X: x + w, Y: y},
+			{X: x + w - i, Y: y + h}, {X: x - w + i, Y: y + h}, {X: x - w, Y: y},
+		}
+	case "octagon":
+		i := math.Min(math.Min(w, h)/2, 10)
+		return []layout.Point{
+			{X: x - w + i, Y: y - h}, {X: x + w - i, Y: y - h}, {X: x + w, Y: y - h + i}, {X: x + w, Y: y + h - i},
+			{X: x + w - i, Y: y + h}, {X: x - w + i, Y: y + h}, {X: x - w, Y: y + h - i}, {X: x - w, Y: y - h + i},
+		}
+	case "cylinder":
+		ry := cylinderRadius(n.node.Height)
+		const segments = 16
+		ps := []layout.Point{}
+		for i := 0; i <= segments; i++ {
+			a := math.Pi * float64(i) / segments
+			ps = append(ps, layout.Point{X: x + w*math.Cos(a), Y: y + h - ry + ry*math.Sin(a)})
+		}
+		for i := 0; i <= segments; i++ {
+			a := math.Pi + math.Pi*float64(i)/segments
+			ps = append(ps, layout.Point{X: x + w*math.Cos(a), Y: y - h + ry + ry*math.Sin(a)})
+		}
+		return ps
+	default:
+		return []layout.Point{{X: x - w, Y: y - h}, {X: x + w, Y: y - h}, {X: x + w, Y: y + h}, {X: x - w, Y: y + h}}
+	}
+}
+
+func cylinderRadius(height float64) float64 {
+	return height * 0.1
+}
@@ -0,0 +1,129 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/layout"
+)
+
+const svgFontFamily = "Helvetica,Arial,sans-serif"
+
+// WriteSVG lays out components and writes them in SVG without Graphviz.
+func WriteSVG(w io.Writer, group *component.Components, cs *component.Components, fs []*face.Face, graph *face.Graph) error {
+	s, err := newScene(group, cs, fs, graph)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"no\"?>\n")
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s\" height=\"%s\" viewBox=\"0 0 %s %s\" font-family=\"%s\">\n",
+		num(s.width), num(s.height), num(s.width), num(s.height), svgFontFamily)
+	fmt.Fprintf(bw, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", s.background.Hex())
+
+	for _, e := range s.edges {
+		if e.style.invisible {
+			continue
+		}
+		fmt.Fprintf(bw, "<g class=\"edge\"><title>%s</title>\n", html.EscapeString(e.edge.From+"->"+e.edge.To))
+		fmt.Fprintf(bw, "<polyline points=\"%s\" fill=\"none\"%s/>\n", svgPoints(e.edge.Points), svgStroke(e.style))
+		if len(e.arrow) > 0 {
+			fmt.Fprintf(bw, "<polygon points=\"%s\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%s\"/>\n",
+				svgPoints(e.arrow), e.style.color.Hex(), e.style.color.Hex(), num(e.style.penWidth))
+		}
+		if e.label != "" {
+			writeSVGText(bw, []string{e.label}, e.labelAt, e.style)
+		}
+		fmt.Fprintf(bw, "</g>\n")
+	}
+
+	for _, n := range s.nodes {
+		if n.style.invisible {
+			continue
+		}
+		fmt.Fprintf(bw, "<g class=\"node\"><title>%s</title>\n", html.EscapeString(n.tooltip))
+		writeSVGShape(bw, n)
+		writeSVGText(bw, n.lines, layout.Point{X: n.node.X, Y: n.node.Y}, n.style)
+		fmt.Fprintf(bw, "</g>\n")
+	}
+
+	fmt.Fprintf(bw, "</svg>\n")
+
+	return bw.Flush()
+}
+
+func writeSVGShape(w io.Writer, n *sceneNode) {
+	fill := "none"
+	if n.style.filled {
+		fill = n.style.fillColor.Hex()
+	}
+	x, y := n.node.X, n.node.Y
+	hw, hh := n.node.Width/2, n.node.Height/2
+
+	switch n.shape {
+	case "none":
+		return
+	case "box":
+		rx := 0.0
+		if n.style.rounded {
+			rx = 6
+		}
+		fmt.Fprintf(w, "<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" rx=\"%s\" fill=\"%s\"%s/>\n",
+			num(x-hw), num(y-hh), num(n.node.Width), num(n.node.Height), num(rx), fill, svgStroke(n.style))
+	case "ellipse", "circle":
+		fmt.Fprintf(w, "<ellipse cx=\"%s\" cy=\"%s\" rx=\"%s\" ry=\"%s\" fill=\"%s\"%s/>\n",
+			num(x), num(y), num(hw), num(hh), fill, svgStroke(n.style))
+	case "cylinder":
+		ry := cylinderRadius(n.node.Height)
+		top, bottom := y-hh+ry, y+hh-ry
+		fmt.Fprintf(w, "<path d=\"M%s,%s L%s,%s A%s,%s 0 0 0 %s,%s L%s,%s A%s,%s 0 0 0 %s,%s Z\" fill=\"%s\"%s/>\n",
+			num(x-hw), num(top), num(x-hw), num(bottom), num(hw), num(ry), num(x+hw), num(bottom),
+			num(x+hw), num(top), num(hw), num(ry), num(x-hw), num(top), fill, svgStroke(n.style))
+		fmt.Fprintf(w, "<path d=\"M%s,%s A%s,%s 0 0 0 %s,%s\" fill=\"none\"%s/>\n",
+			num(x-hw), num(top), num(hw), num(ry), num(x+hw), num(top), svgStroke(n.style))
+	default:
+		fmt.Fprintf(w, "<polygon points=\"%s\" fill=\"%s\"%s/>\n", svgPoints(n.outline()), fill, svgStroke(n.style))
+	}
+}
+
+func writeSVGText(w io.Writer, lines []string, center layout.Point, style *sceneStyle) {
+	lineHeight := style.fontSize * lineHeightRatio
+	y := center.Y - lineHeight*float64(len(lines)-1)/2
+	for i, l := range lines {
+		fmt.Fprintf(w, "<text x=\"%s\" y=\"%s\" text-anchor=\"middle\" dominant-baseline=\"central\" font-size=\"%s\" fill=\"%s\">%s</text>\n",
+			num(center.X), num(y+lineHeight*float64(i)), num(style.fontSize), style.fontColor.Hex(), html.EscapeString(l))
+	}
+}
+
+func svgStroke(style *sceneStyle) string {
+	s := fmt.Sprintf(" stroke=\"%s\" stroke-width=\"%s\"", style.color.Hex(), num(style.penWidth))
+	if style.dashed {
+		s += " stroke-dasharray=\"5,2\""
+	}
+	return s
+}
+
+func svgPoints(ps []layout.Point) string {
+	s := make([]string, 0, len(ps))
+	for _, p := range ps {
+		s = append(s, num(p.X)+","+num(p.Y))
+	}
+	return strings.Join(s, " ")
+}
+
+// num formats a coordinate with up to two decimal places.
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package server
+
+// indexHTML is a page that shows a diagram of the query in the form and refreshes it
+// every time the server reloads the definitions.
+const indexHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>felipe</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; margin: 1em; }
+form input { width: 20em; }
+#error { color: #B00020; white-space: pre-wrap; }
+#graph { overflow: auto; }
+</style>
+</head>
+<body>
+<form id="query">
+<input name="filter" placeholder="filter (e.g. kind=service)">
+<input name="complement" placeholder="complementation (e.g. dep=1,rdep=1)">
+<button type="submit">Query</button>
+<a id="dot" href="/graph.dot">DOT</a>
+<a id="json" href="/api/query">JSON</a>
+</form>
+<p id="error"></p>
+<div id="graph"></div>
+<script>
+const form = document.getElementById("query");
+const params = () => new URLSearchParams(new FormData(form)).toString();
+
+async function refresh() {
+  const q = params();
+  document.getElementById("dot").href = "/graph.dot?" + q;
+  document.getElementById("json").href = "/api/query?" + q;
+  const res = await fetch("/graph.svg?" + q);
+  const body = await res.text();
+  if (!res.ok) {
+    document.getElementById("error").textContent = body;
+    return;
+  }
+  document.getElementById("error").textContent = "";
+  document.getElementById("graph").innerHTML = body;
+}
+
+new URLSearchParams(location.search).forEach((v, k) => {
+  if (form.elements[k]) {
+    form.elements[k].value = v;
+  }
+});
+form.addEventListener("submit", (e) => {
+  e.preventDefault();
+  history.replaceState(null, "", "?" + params());
+  refresh();
+});
+new EventSource("/events").onmessage = refresh;
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nihei9/felipe/api"
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/face"
+	"github.com/nihei9/felipe/loader"
+	"github.com/nihei9/felipe/render"
+	"github.com/nihei9/felipe/watch"
+)
+
+// Server serves diagrams and a JSON API of components defined in a directory. It reloads the
+// definitions when they change and notifies browsers so that they refresh diagrams.
+type Server struct {
+	Dir      string
+	FaceFile string
+
+	// Overlays are names of overlays applied to the definitions in order. See loader.OverlayFile.
+	Overlays []string
+
+	mux *http.ServeMux
+
+	mu          sync.RWMutex
+	components  *component.Components
+	faces       []*face.Face
+	graph       *face.Graph
+	err         error
+	version     int
+	subscribers map[chan int]bool
+}
+
+func New(dir string, faceFile string) *Server {
+	s := &Server{
+		Dir:         dir,
+		FaceFile:    faceFile,
+		mux:         http.NewServeMux(),
+		components:  component.NewComponents(),
+		subscribers: map[chan int]bool{},
+	}
+	s.mux.HandleFunc("/", s.handleIndex)
+	s.mux.HandleFunc("/events", s.handleEvents)
+	s.mux.HandleFunc("/graph.svg", s.handleSVG)
+	s.mux.HandleFunc("/graph.dot", s.handleDOT)
+	s.mux.Handle("/api/", &api.Handler{
+		Source: func() (*component.Components, error) {
+			snap, err := s.snapshot()
+			if err != nil {
+				return nil, err
+			}
+			return snap.components, nil
+		},
+		Prefix: "/api",
+	})
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Reload loads the definitions and notifies subscribers. When the definitions are invalid, the server
+// responds with the error until the definitions are fixed.
+func (s *Server) Reload() error {
+	cs, fs, graph, err := s.load()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	if err == nil {
+		s.components = cs
+		s.faces = fs
+		s.graph = graph
+	}
+	s.version++
+	for ch := range s.subscribers {
+		select {
+		case ch <- s.version:
+		default:
+		}
+	}
+
+	return err
+}
+
+// Watch reloads the definitions every time they change until stop is closed.
+func (s *Server) Watch(stop <-chan struct{}, interval time.Duration, reloaded func(err error)) error {
+	paths := []string{s.Dir}
+	if s.FaceFile != "" {
+		paths = append(paths, s.FaceFile)
+	}
+	for _, name := range s.Overlays {
+		paths = append(paths, loader.OverlayFile(s.Dir, name))
+	}
+
+	return watch.Poller{
+		Paths:    paths,
+		Interval: interval,
+	}.Run(stop, func() {
+		err := s.Reload()
+		if reloaded != nil {
+			reloaded(err)
+		}
+	})
+}
+
+func (s *Server) load() (*component.Components, []*face.Face, *face.Graph, error) {
+	excludes := []string{}
+	if s.FaceFile != "" {
+		excludes = append(excludes, s.FaceFile)
+	}
+	defFiles, err := loader.DefinitionFiles(s.Dir, excludes...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	overlayFiles := []string{}
+	for _, name := range s.Overlays {
+		overlayFiles = append(overlayFiles, loader.OverlayFile(s.Dir, name))
+	}
+	cs, err := loader.LoadFilesWithOverlays(defFiles, overlayFiles)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if s.FaceFile == "" {
+		return cs, []*face.Face{}, nil, nil
+	}
+	faces, err := loader.LoadFaces(s.FaceFile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return cs, faces.Faces, faces.Graph, nil
+}
+
+func (s *Server) subscribe() chan int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch := make(chan int, 1)
+	ch <- s.version
+	s.subscribers[ch] = true
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscribers, ch)
+}
+
+type snapshot struct {
+	components *component.Components
+	faces      []*face.Face
+	graph      *face.Graph
+}
+
+func (s *Server) snapshot() (*snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &snapshot{
+		components: s.components,
+		faces:      s.faces,
+		graph:      s.graph,
+	}, nil
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, indexHTML)
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+	for {
+		select {
+		case v := <-ch:
+			fmt.Fprintf(w, "data: %d\n\n", v)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) handleSVG(w http.ResponseWriter, r *http.Request) {
+	s.handleGraph(w, r, "image/svg+xml", render.WriteSVG)
+}
+
+func (s *Server) handleDOT(w http.ResponseWriter, r *http.Request) {
+	s.handleGraph(w, r, "text/vnd.graphviz; charset=utf-8", render.WriteDOT)
+}
+
+func (s *Server) handleGraph(w http.ResponseWriter, r *http.Request, contentType string, write func(io.Writer, *component.Components, *component.Components, []*face.Face, *face.Graph) error) {
+	snap, err := s.snapshot()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	result, err := api.Query(snap.components, r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var b bytes.Buffer
+	err = write(&b, result, snap.components, snap.faces, snap.graph)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(b.Bytes())
+}
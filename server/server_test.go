@@ -0,0 +1,226 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+	"github.com/nihei9/felipe/definitions"
+)
+
+const testComponents = `
+version: 1
+kind: components
+components:
+- id: a
+  labels:
+    role: entry
+  dependencies:
+  - id: b
+- id: b
+  dependencies:
+  - id: c
+- id: c
+`
+
+const testFaces = `
+version: 1
+kind: faces
+faces:
+- targets:
+    match_labels:
+      role: entry
+  attributes:
+    shape: box
+`
+
+func newTestServer(t *testing.T) (*Server, string) {
+	dir, err := ioutil.TempDir("", "felipe-server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "components.yaml"), []byte(testComponents), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	faceFile := filepath.Join(dir, "faces.yaml")
+	err = ioutil.WriteFile(faceFile, []byte(testFaces), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(dir, faceFile)
+	err = s.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, dir
+}
+
+func get(t *testing.T, s *Server, url string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	return rec
+}
+
+func componentIDs(t *testing.T, rec *httptest.ResponseRecorder) []string {
+	def := &definitions.ComponentsDefinition{}
+	err := json.NewDecoder(rec.Body).Decode(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, c := range def.Components {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestServer(t *testing.T) {
+	s, dir := newTestServer(t)
+	defer os.RemoveAll(dir)
+
+	t.Run("components", func(t *testing.T) {
+		rec := get(t, s, "/api/components")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status; got: %v", rec.Code)
+		}
+		if ids, want := componentIDs(t, rec), []string{"a", "b", "c"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("unexpected components; want: %v, got: %v", want, ids)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		rec := get(t, s, "/api/query?filter=role%3Dentry&complement=dep%3D1")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status; got: %v", rec.Code)
+		}
+		if ids, want := componentIDs(t, rec), []string{"a", "b"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("unexpected components; want: %v, got: %v", want, ids)
+		}
+	})
+
+	t.Run("malformed query", func(t *testing.T) {
+		rec := get(t, s, "/api/query?complement=foo")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("unexpected status; got: %v", rec.Code)
+		}
+	})
+
+	t.Run("paths", func(t *testing.T) {
+		rec := get(t, s, "/api/paths?from=a&to=c")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status; got: %v", rec.Code)
+		}
+		res := &struct {
+			Paths [][]component.ComponentID `json:"paths"`
+		}{}
+		err := json.NewDecoder(rec.Body).Decode(res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := [][]component.ComponentID{{"a", "b", "c"}}
+		if !reflect.DeepEqual(res.Paths, want) {
+			t.Errorf("unexpected paths; want: %v, got: %v", want, res.Paths)
+		}
+	})
+
+	t.Run("graph", func(t *testing.T) {
+		rec := get(t, s, "/graph.svg?filter=role%3Dentry")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status; got: %v", rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "<rect x=") {
+			t.Errorf("faces must be applied to the graph; got:\n%v", rec.Body.String())
+		}
+	})
+
+	t.Run("invalid definitions", func(t *testing.T) {
+		path := filepath.Join(dir, "components.yaml")
+		err := ioutil.WriteFile(path, []byte("version: 1\nkind: components\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ioutil.WriteFile(path, []byte(testComponents), 0644)
+
+		if err := s.Reload(); err == nil {
+			t.Fatal("an error must occur")
+		}
+		rec := get(t, s, "/api/components")
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("unexpected status; got: %v", rec.Code)
+		}
+	})
+}
+
+func TestServer_overlays(t *testing.T) {
+	s, dir := newTestServer(t)
+	defer os.RemoveAll(dir)
+
+	err := os.Mkdir(filepath.Join(dir, "overlays"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "overlays", "prod.yaml"), []byte(`
+version: 1
+kind: overlay
+add:
+- id: cdn
+  dependencies:
+  - id: a
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Overlays = []string{"prod"}
+	err = s.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := get(t, s, "/api/components")
+	if ids, want := componentIDs(t, rec), []string{"a", "b", "c", "cdn"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("the overlay must be applied; want: %v, got: %v", want, ids)
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	s, dir := newTestServer(t)
+	defer os.RemoveAll(dir)
+
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	r := bufio.NewReader(res.Body)
+
+	readEvent := func() string {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.ReadString('\n')
+		return strings.TrimSpace(line)
+	}
+
+	if ev := readEvent(); ev != "data: 1" {
+		t.Errorf("the current version must be sent first; got: %v", ev)
+	}
+	s.Reload()
+	if ev := readEvent(); ev != "data: 2" {
+		t.Errorf("a reload must be notified; got: %v", ev)
+	}
+}
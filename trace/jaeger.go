@@ -0,0 +1,137 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+type jaegerExport struct {
+	Data []*jaegerTrace `json:"data"`
+	jaegerTrace
+}
+
+type jaegerTrace struct {
+	TraceID   string                    `json:"traceID"`
+	Spans     []*jaegerSpan             `json:"spans"`
+	Processes map[string]*jaegerProcess `json:"processes"`
+}
+
+type jaegerSpan struct {
+	TraceID      string             `json:"traceID"`
+	SpanID       string             `json:"spanID"`
+	ParentSpanID string             `json:"parentSpanID"`
+	References   []*jaegerReference `json:"references"`
+	Duration     int64              `json:"duration"`
+	Tags         []*jaegerTag       `json:"tags"`
+	ProcessID    string             `json:"processID"`
+	Process      *jaegerProcess     `json:"process"`
+}
+
+type jaegerReference struct {
+	RefType string `json:"refType"`
+	TraceID string `json:"traceID"`
+	SpanID  string `json:"spanID"`
+}
+
+type jaegerTag struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+type jaegerProcess struct {
+	ServiceName string `json:"serviceName"`
+}
+
+func ReadJaeger(r io.Reader) ([]*Span, error) {
+	spans := []*Span{}
+	dec := json.NewDecoder(r)
+	for {
+		export := &jaegerExport{}
+		err := dec.Decode(export)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		traces := export.Data
+		if len(export.Spans) > 0 {
+			traces = append(traces, &export.jaegerTrace)
+		}
+		for _, t := range traces {
+			if t == nil {
+				continue
+			}
+			for _, s := range t.Spans {
+				if s == nil {
+					continue
+				}
+				span, err := s.toSpan(t)
+				if err != nil {
+					return nil, err
+				}
+				spans = append(spans, span)
+			}
+		}
+	}
+
+	return spans, nil
+}
+
+func (s *jaegerSpan) toSpan(t *jaegerTrace) (*Span, error) {
+	if s.SpanID == "" {
+		return nil, fmt.Errorf("a jaeger span must have `spanID`")
+	}
+
+	traceID := s.TraceID
+	if traceID == "" {
+		traceID = t.TraceID
+	}
+
+	parentID := s.ParentSpanID
+	for _, ref := range s.References {
+		if ref == nil || ref.RefType != "CHILD_OF" {
+			continue
+		}
+		if ref.TraceID != "" && ref.TraceID != traceID {
+			continue
+		}
+		parentID = ref.SpanID
+		break
+	}
+
+	process := s.Process
+	if process == nil {
+		process = t.Processes[s.ProcessID]
+	}
+	if process == nil || process.ServiceName == "" {
+		return nil, fmt.Errorf("the service of the jaeger span `%s` is unknown", s.SpanID)
+	}
+
+	return &Span{
+		TraceID:  traceID,
+		SpanID:   s.SpanID,
+		ParentID: parentID,
+		Service:  process.ServiceName,
+		Duration: time.Duration(s.Duration) * time.Microsecond,
+		Error:    s.hasError(),
+	}, nil
+}
+
+func (s *jaegerSpan) hasError() bool {
+	for _, tag := range s.Tags {
+		if tag == nil || tag.Key != "error" {
+			continue
+		}
+		switch v := tag.Value.(type) {
+		case bool:
+			return v
+		case string:
+			return v == "true"
+		}
+	}
+	return false
+}
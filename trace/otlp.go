@@ -0,0 +1,156 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	otlpServiceNameKey     = "service.name"
+	otlpStatusCodeError    = 2
+	otlpStatusCodeErrorStr = "STATUS_CODE_ERROR"
+)
+
+type otlpExport struct {
+	ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource                    *otlpResource     `json:"resource"`
+	ScopeSpans                  []*otlpScopeSpans `json:"scopeSpans"`
+	InstrumentationLibrarySpans []*otlpScopeSpans `json:"instrumentationLibrarySpans"`
+}
+
+type otlpResource struct {
+	Attributes []*otlpAttribute `json:"attributes"`
+}
+
+type otlpAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpScopeSpans struct {
+	Spans []*otlpSpan `json:"spans"`
+}
+
+type otlpSpan struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId"`
+	StartTimeUnixNano otlpUint64  `json:"startTimeUnixNano"`
+	EndTimeUnixNano   otlpUint64  `json:"endTimeUnixNano"`
+	Status            *otlpStatus `json:"status"`
+}
+
+type otlpStatus struct {
+	Code interface{} `json:"code"`
+}
+
+// otlpUint64 accepts both JSON numbers and strings because the OTLP/JSON
+// encoding represents 64-bit integers as strings.
+type otlpUint64 uint64
+
+func (n *otlpUint64) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("an OTLP integer is malformed; got: %v", string(data))
+	}
+	*n = otlpUint64(v)
+	return nil
+}
+
+func ReadOTLP(r io.Reader) ([]*Span, error) {
+	spans := []*Span{}
+	dec := json.NewDecoder(r)
+	for {
+		export := &otlpExport{}
+		err := dec.Decode(export)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rs := range export.ResourceSpans {
+			if rs == nil {
+				continue
+			}
+			service := rs.serviceName()
+			if service == "" {
+				return nil, fmt.Errorf("an OTLP resource must have the `%s` attribute", otlpServiceNameKey)
+			}
+			for _, ss := range append(rs.ScopeSpans, rs.InstrumentationLibrarySpans...) {
+				if ss == nil {
+					continue
+				}
+				for _, s := range ss.Spans {
+					if s == nil {
+						continue
+					}
+					if s.SpanID == "" {
+						return nil, fmt.Errorf("an OTLP span must have `spanId`")
+					}
+					spans = append(spans, s.toSpan(service))
+				}
+			}
+		}
+	}
+
+	return spans, nil
+}
+
+func (rs *otlpResourceSpans) serviceName() string {
+	if rs.Resource == nil {
+		return ""
+	}
+	for _, attr := range rs.Resource.Attributes {
+		if attr != nil && attr.Key == otlpServiceNameKey {
+			return attr.Value.StringValue
+		}
+	}
+	return ""
+}
+
+func (s *otlpSpan) toSpan(service string) *Span {
+	var d time.Duration
+	if s.EndTimeUnixNano > s.StartTimeUnixNano {
+		d = time.Duration(s.EndTimeUnixNano - s.StartTimeUnixNano)
+	}
+
+	return &Span{
+		TraceID:  s.TraceID,
+		SpanID:   s.SpanID,
+		ParentID: s.ParentSpanID,
+		Service:  service,
+		Duration: d,
+		Error:    s.hasError(),
+	}
+}
+
+func (s *otlpSpan) hasError() bool {
+	if s.Status == nil {
+		return false
+	}
+	switch code := s.Status.Code.(type) {
+	case float64:
+		return int(code) == otlpStatusCodeError
+	case string:
+		return code == otlpStatusCodeErrorStr
+	}
+	return false
+}
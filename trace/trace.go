@@ -0,0 +1,122 @@
+package trace
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nihei9/felipe/component"
+)
+
+const (
+	RelationObserved = "observed"
+
+	AttributeCalls      = "calls"
+	AttributeErrors     = "errors"
+	AttributeErrorRate  = "error_rate"
+	AttributeLatencyP50 = "latency_p50"
+	AttributeLatencyP90 = "latency_p90"
+	AttributeLatencyP99 = "latency_p99"
+)
+
+type Span struct {
+	TraceID  string
+	SpanID   string
+	ParentID string
+	Service  string
+	Duration time.Duration
+	Error    bool
+}
+
+type spanKey struct {
+	traceID string
+	spanID  string
+}
+
+type callStats struct {
+	durations []time.Duration
+	errors    int
+}
+
+func Aggregate(spans []*Span) *component.Components {
+	index := map[spanKey]*Span{}
+	services := []string{}
+	knownServices := map[string]bool{}
+	for _, s := range spans {
+		index[spanKey{traceID: s.TraceID, spanID: s.SpanID}] = s
+		if s.Service == "" || knownServices[s.Service] {
+			continue
+		}
+		knownServices[s.Service] = true
+		services = append(services, s.Service)
+	}
+	sort.Strings(services)
+
+	stats := map[string]map[string]*callStats{}
+	for _, s := range spans {
+		if s.ParentID == "" || s.Service == "" {
+			continue
+		}
+		parent, ok := index[spanKey{traceID: s.TraceID, spanID: s.ParentID}]
+		if !ok || parent.Service == "" || parent.Service == s.Service {
+			continue
+		}
+
+		callees, ok := stats[parent.Service]
+		if !ok {
+			callees = map[string]*callStats{}
+			stats[parent.Service] = callees
+		}
+		st, ok := callees[s.Service]
+		if !ok {
+			st = &callStats{}
+			callees[s.Service] = st
+		}
+		st.durations = append(st.durations, s.Duration)
+		if s.Error {
+			st.errors++
+		}
+	}
+
+	cs := component.NewComponents()
+	for _, svc := range services {
+		c := component.NewComponent(component.NilComponentID, component.ComponentID(svc))
+		for callee, st := range stats[svc] {
+			c.DependOn(component.ComponentID(callee), &component.Relation{
+				Description: RelationObserved,
+				Attributes:  st.attributes(),
+			})
+		}
+		cs.Add(c)
+	}
+
+	return cs
+}
+
+func (st *callStats) attributes() map[string]string {
+	sort.Slice(st.durations, func(i, j int) bool {
+		return st.durations[i] < st.durations[j]
+	})
+	calls := len(st.durations)
+
+	return map[string]string{
+		AttributeCalls:      fmt.Sprintf("%d", calls),
+		AttributeErrors:     fmt.Sprintf("%d", st.errors),
+		AttributeErrorRate:  fmt.Sprintf("%.4f", float64(st.errors)/float64(calls)),
+		AttributeLatencyP50: percentile(st.durations, 50).String(),
+		AttributeLatencyP90: percentile(st.durations, 90).String(),
+		AttributeLatencyP99: percentile(st.durations, 99).String(),
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) <= 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,118 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nihei9/felipe/component"
+)
+
+func TestReadJaeger(t *testing.T) {
+	data := `
+{
+  "data": [
+    {
+      "traceID": "t1",
+      "spans": [
+        {"traceID": "t1", "spanID": "s1", "duration": 3000, "processID": "p1"},
+        {"traceID": "t1", "spanID": "s2", "duration": 1000, "processID": "p2",
+         "references": [{"refType": "CHILD_OF", "traceID": "t1", "spanID": "s1"}]},
+        {"traceID": "t1", "spanID": "s3", "duration": 2000, "processID": "p2",
+         "references": [{"refType": "CHILD_OF", "traceID": "t1", "spanID": "s1"}],
+         "tags": [{"key": "error", "type": "bool", "value": true}]},
+        {"traceID": "t1", "spanID": "s4", "duration": 500, "processID": "p2",
+         "references": [{"refType": "CHILD_OF", "traceID": "t1", "spanID": "s3"}]}
+      ],
+      "processes": {
+        "p1": {"serviceName": "frontend"},
+        "p2": {"serviceName": "backend"}
+      }
+    }
+  ]
+}
+`
+	spans, err := ReadJaeger(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 4 {
+		t.Fatalf("unexpected span count; want: 4, got: %v", len(spans))
+	}
+
+	cs := Aggregate(spans)
+	testDependency(t, cs, "frontend", "backend", map[string]string{
+		AttributeCalls:      "2",
+		AttributeErrors:     "1",
+		AttributeErrorRate:  "0.5000",
+		AttributeLatencyP50: "1ms",
+		AttributeLatencyP90: "2ms",
+		AttributeLatencyP99: "2ms",
+	})
+
+	backend, ok := cs.Get("backend")
+	if !ok {
+		t.Fatal("`backend` is missing")
+	}
+	if len(backend.Dependencies) != 0 {
+		t.Errorf("calls within a service must not be dependencies; got: %v", backend.Dependencies)
+	}
+}
+
+func TestReadOTLP(t *testing.T) {
+	data := `
+{"resourceSpans": [
+  {"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "frontend"}}]},
+   "scopeSpans": [{"spans": [
+     {"traceId": "t1", "spanId": "s1", "startTimeUnixNano": "1000000000", "endTimeUnixNano": "1005000000"}
+   ]}]},
+  {"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "db"}}]},
+   "scopeSpans": [{"spans": [
+     {"traceId": "t1", "spanId": "s2", "parentSpanId": "s1", "startTimeUnixNano": "1001000000", "endTimeUnixNano": "1004000000", "status": {"code": 2}}
+   ]}]}
+]}
+{"resourceSpans": [
+  {"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "db"}}]},
+   "scopeSpans": [{"spans": [
+     {"traceId": "t2", "spanId": "s3", "parentSpanId": "s9", "startTimeUnixNano": 1, "endTimeUnixNano": 2}
+   ]}]}
+]}
+`
+	spans, err := ReadOTLP(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 3 {
+		t.Fatalf("unexpected span count; want: 3, got: %v", len(spans))
+	}
+
+	cs := Aggregate(spans)
+	testDependency(t, cs, "frontend", "db", map[string]string{
+		AttributeCalls:      "1",
+		AttributeErrors:     "1",
+		AttributeErrorRate:  "1.0000",
+		AttributeLatencyP50: "3ms",
+		AttributeLatencyP90: "3ms",
+		AttributeLatencyP99: "3ms",
+	})
+}
+
+func testDependency(t *testing.T, cs *component.Components, from, to component.ComponentID, attrs map[string]string) {
+	t.Helper()
+
+	c, ok := cs.Get(from)
+	if !ok {
+		t.Fatalf("`%v` is missing", from)
+	}
+	rel, ok := c.Dependencies[to]
+	if !ok {
+		t.Fatalf("`%v` must depend on `%v`", from, to)
+	}
+	if rel.Description != RelationObserved {
+		t.Errorf("unexpected relation; want: %v, got: %v", RelationObserved, rel.Description)
+	}
+	for k, v := range attrs {
+		if rel.Attributes[k] != v {
+			t.Errorf("unexpected attribute `%v`; want: %v, got: %v", k, v, rel.Attributes[k])
+		}
+	}
+}
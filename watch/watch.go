@@ -0,0 +1,145 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const DefaultInterval = 500 * time.Millisecond
+
+// Poller detects changes of files by polling their sizes and modification times. It does not depend
+// on OS-specific notification mechanisms, so it works on any platform and file system.
+type Poller struct {
+	// Paths are files or directories to watch. Definition files (`*.yaml`) directly under the directories
+	// are watched, so that outputs written to the directories do not trigger changes.
+	Paths []string
+
+	// Excludes are files not to be watched even if they are definition files under Paths, such as an
+	// output file written in YAML.
+	Excludes []string
+
+	// Interval is the interval of polling. DefaultInterval is used when it is zero.
+	Interval time.Duration
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+type snapshot map[string]fileState
+
+// Run calls changed every time files are created, modified or removed until stop is closed.
+func (p Poller) Run(stop <-chan struct{}, changed func()) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	prev, err := p.snapshot()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			cur, err := p.snapshot()
+			if err != nil {
+				return err
+			}
+			if !cur.equal(prev) {
+				changed()
+			}
+			prev = cur
+		}
+	}
+}
+
+func (p Poller) snapshot() (snapshot, error) {
+	s := snapshot{}
+	for _, path := range p.Paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !info.IsDir() {
+			s[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+			continue
+		}
+
+		files, err := filepath.Glob(filepath.Join(path, "*.yaml"))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if p.excluded(f) {
+				continue
+			}
+			info, err := os.Stat(f)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			if info.IsDir() {
+				continue
+			}
+			s[f] = fileState{size: info.Size(), modTime: info.ModTime()}
+		}
+	}
+
+	return s, nil
+}
+
+func (p Poller) excluded(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, e := range p.Excludes {
+		eAbs, err := filepath.Abs(e)
+		if err == nil && eAbs == abs {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s snapshot) equal(t snapshot) bool {
+	if len(s) != len(t) {
+		return false
+	}
+	for path, st := range s {
+		tt, ok := t[path]
+		if !ok || tt.size != st.size || !tt.modTime.Equal(st.modTime) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Rerun calls run once and then every time files change until stop is closed. Errors of run are passed
+// to report and do not stop watching, so that users can fix definitions while watching them.
+func Rerun(p Poller, stop <-chan struct{}, run func() error, report func(err error)) error {
+	do := func() {
+		err := run()
+		if err != nil {
+			report(err)
+		}
+	}
+
+	do()
+	return p.Run(stop, do)
+}
@@ -0,0 +1,144 @@
+package watch
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPoller(t *testing.T) {
+	dir, err := ioutil.TempDir("", "felipe-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	changed := make(chan struct{}, 10)
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- Poller{
+			Paths:    []string{dir},
+			Excludes: []string{filepath.Join(dir, "out.yaml")},
+			Interval: 10 * time.Millisecond,
+		}.Run(stop, func() {
+			changed <- struct{}{}
+		})
+	}()
+
+	wait := func(caption string) {
+		select {
+		case <-changed:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("a change must be detected: %v", caption)
+		}
+	}
+
+	// Wait for the first snapshot.
+	time.Sleep(50 * time.Millisecond)
+
+	path := filepath.Join(dir, "components.yaml")
+	err = ioutil.WriteFile(path, []byte("version: 1\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait("create")
+
+	err = ioutil.WriteFile(path, []byte("version: 1\nkind: components\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait("modify")
+
+	err = os.Remove(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait("remove")
+
+	// Outputs written to the directory must not be detected.
+	for _, name := range []string{"graph.dot", ".graph.dot.tmp", "out.yaml"} {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte("digraph {}\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case <-changed:
+		t.Fatal("an output must not be detected as a change")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(stop)
+	err = <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRerun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "felipe-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "components.yaml")
+
+	runs := make(chan struct{}, 10)
+	reports := make(chan error, 10)
+	stop := make(chan struct{})
+	done := make(chan error)
+	count := 0
+	go func() {
+		done <- Rerun(Poller{
+			Paths:    []string{path},
+			Interval: 10 * time.Millisecond,
+		}, stop, func() error {
+			count++
+			runs <- struct{}{}
+			if count == 1 {
+				return errors.New("invalid definitions")
+			}
+			return nil
+		}, func(err error) {
+			reports <- err
+		})
+	}()
+
+	wait := func(ch <-chan struct{}, caption string) {
+		select {
+		case <-ch:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out: %v", caption)
+		}
+	}
+
+	wait(runs, "the first run")
+	select {
+	case err := <-reports:
+		if err.Error() != "invalid definitions" {
+			t.Errorf("unexpected error; got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("an error must be reported")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	err = ioutil.WriteFile(path, []byte("version: 1\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait(runs, "a run after the change")
+
+	close(stop)
+	err = <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 0 {
+		t.Errorf("a successful run must not be reported")
+	}
+}